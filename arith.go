@@ -0,0 +1,158 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "math/bits"
+
+// AddChecked returns u + m and whether the sum fits in 128 bits.
+// On overflow the wrapped-around sum is returned with ok == false.
+func (u Uint128) AddChecked(m Uint128) (sum Uint128, ok bool) {
+	sum, carry := u.AddCarry(m, 0)
+	return sum, carry == 0
+}
+
+// SubChecked returns u - m and whether the difference is non-negative.
+// On underflow the wrapped-around difference is returned with ok == false.
+func (u Uint128) SubChecked(m Uint128) (diff Uint128, ok bool) {
+	diff, borrow := u.SubBorrow(m, 0)
+	return diff, borrow == 0
+}
+
+// MulChecked returns u * m and whether the product fits in 128 bits.
+// On overflow the low 128 bits of the product are returned with
+// ok == false.
+func (u Uint128) MulChecked(m Uint128) (prod Uint128, ok bool) {
+	if u.hi != 0 && m.hi != 0 {
+		return u.Mul(m), false
+	}
+	// At most one of the cross products is non-zero.
+	hi, lo := bits.Mul64(u.lo, m.lo)
+	c1, x1 := bits.Mul64(u.hi, m.lo)
+	c2, x2 := bits.Mul64(u.lo, m.hi)
+	hi, carry := bits.Add64(hi, x1+x2, 0)
+	return Uint128{hi, lo}, c1|c2|carry == 0
+}
+
+// Mul64Checked returns u * m and whether the product fits in 128 bits.
+// On overflow the low 128 bits of the product are returned with
+// ok == false.
+func (u Uint128) Mul64Checked(m uint64) (prod Uint128, ok bool) {
+	hi, lo := bits.Mul64(u.lo, m)
+	c, x := bits.Mul64(u.hi, m)
+	hi, carry := bits.Add64(hi, x, 0)
+	return Uint128{hi, lo}, c|carry == 0
+}
+
+// AddSat returns u + m, saturating at 2^128-1 on overflow.
+func (u Uint128) AddSat(m Uint128) Uint128 {
+	if sum, ok := u.AddChecked(m); ok {
+		return sum
+	}
+	return Max
+}
+
+// SubSat returns u - m, saturating at 0 on underflow.
+func (u Uint128) SubSat(m Uint128) Uint128 {
+	if diff, ok := u.SubChecked(m); ok {
+		return diff
+	}
+	return Uint128{}
+}
+
+// MulSat returns u * m, saturating at 2^128-1 on overflow.
+func (u Uint128) MulSat(m Uint128) Uint128 {
+	if prod, ok := u.MulChecked(m); ok {
+		return prod
+	}
+	return Max
+}
+
+// Neg returns the two's-complement negation of u, that is 0 - u
+// modulo 2^128.
+func (u Uint128) Neg() Uint128 {
+	lo, borrow := bits.Sub64(0, u.lo, 0)
+	hi, _ := bits.Sub64(0, u.hi, borrow)
+	return Uint128{hi, lo}
+}
+
+// AbsDiff returns |u - v|.
+func (u Uint128) AbsDiff(v Uint128) Uint128 {
+	if u.Cmp(v) < 0 {
+		return v.Sub(u)
+	}
+	return u.Sub(v)
+}
+
+// Min returns the smaller of u and v.
+func (u Uint128) Min(v Uint128) Uint128 {
+	if v.Cmp(u) < 0 {
+		return v
+	}
+	return u
+}
+
+// Max returns the larger of u and v.
+func (u Uint128) Max(v Uint128) Uint128 {
+	if v.Cmp(u) > 0 {
+		return v
+	}
+	return u
+}
+
+// Clamp returns u limited to the range [lo, hi].
+// If lo > hi, the result is hi.
+func (u Uint128) Clamp(lo, hi Uint128) Uint128 {
+	return u.Max(lo).Min(hi)
+}
+
+// Midpoint returns floor((u + v) / 2), computed without the
+// intermediate sum overflowing 128 bits.
+func (u Uint128) Midpoint(v Uint128) Uint128 {
+	// u + v == 2*(u&v) + (u^v).
+	x := u.Xor(v)
+	return u.And(v).Add(x.Rsh(1))
+}
+
+// Exp returns u**e and whether the result fits in 128 bits, computed by
+// square-and-multiply. On overflow the result modulo 2^128 is returned
+// with ok == false. By convention 0**0 == 1.
+func (u Uint128) Exp(e Uint128) (r Uint128, ok bool) {
+	r, ok = Uint128{0, 1}, true
+	b, bOK := u, true // bOK reports whether b is exact, not wrapped
+	for !e.IsZero() {
+		if e.lo&1 != 0 {
+			var mulOK bool
+			r, mulOK = r.MulChecked(b)
+			ok = ok && mulOK && bOK
+		}
+		e = e.Rsh(1)
+		if !e.IsZero() {
+			var sqOK bool
+			b, sqOK = b.MulChecked(b)
+			bOK = bOK && sqOK
+		}
+	}
+	return r, ok
+}
+
+// RoundDown returns the largest multiple of m that is less than or
+// equal to u. It panics if m is zero.
+func (u Uint128) RoundDown(m Uint128) Uint128 {
+	if m.IsPowerOfTwo() {
+		return u.And(m.SubOne().Not())
+	}
+	return u.Sub(u.Mod(m))
+}
+
+// RoundUp returns the smallest multiple of m that is greater than or
+// equal to u. If that multiple does not fit in 128 bits, the result
+// modulo 2^128 is returned with ok == false. It panics if m is zero.
+func (u Uint128) RoundUp(m Uint128) (r Uint128, ok bool) {
+	d := u.RoundDown(m)
+	if d == u {
+		return u, true
+	}
+	return d.AddChecked(m)
+}
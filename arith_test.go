@@ -0,0 +1,233 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestChecked(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b         uint128
+		addOK, subOK bool
+		mulOK        bool
+	}{
+		{uint128{0, 0}, uint128{0, 0}, true, true, true},
+		{uint128{0, 2}, uint128{0, 3}, true, false, true},
+		{uint128{0, 3}, uint128{0, 2}, true, true, true},
+		{max, uint128{0, 0}, true, true, true},
+		{max, uint128{0, 1}, false, true, true},
+		{max, uint128{0, 2}, false, true, false},
+		{uint128{1, 0}, uint128{1, 0}, true, true, false},
+		{uint128{1 << 63, 0}, uint128{0, 2}, true, true, false},
+		{uint128{1<<63 - 1, ^uint64(0)}, uint128{0, 2}, true, true, true},
+		{uint128{0, 1 << 63}, uint128{1, 0}, true, false, true},
+		{uint128{0, ^uint64(0)}, uint128{0, ^uint64(0)}, true, true, true},
+		{uint128{1, 1}, uint128{^uint64(0), 0}, false, false, false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.a.AddChecked(tt.b); got != tt.a.Add(tt.b) || ok != tt.addOK {
+			t.Errorf("%v.AddChecked(%v) = %v, %v; want %v, %v", tt.a, tt.b, got, ok, tt.a.Add(tt.b), tt.addOK)
+		}
+		if got, ok := tt.a.SubChecked(tt.b); got != tt.a.Sub(tt.b) || ok != tt.subOK {
+			t.Errorf("%v.SubChecked(%v) = %v, %v; want %v, %v", tt.a, tt.b, got, ok, tt.a.Sub(tt.b), tt.subOK)
+		}
+		if got, ok := tt.a.MulChecked(tt.b); got != tt.a.Mul(tt.b) || ok != tt.mulOK {
+			t.Errorf("%v.MulChecked(%v) = %v, %v; want %v, %v", tt.a, tt.b, got, ok, tt.a.Mul(tt.b), tt.mulOK)
+		}
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, tt := range mulTests {
+		want := new(big.Int).Mul(toBig(tt.a), new(big.Int).SetUint64(tt.b.lo))
+		got, ok := tt.a.Mul64Checked(tt.b.lo)
+		if got != tt.a.Mul64(tt.b.lo) || ok != (want.Cmp(limit) < 0) {
+			t.Errorf("%v.Mul64Checked(%#x) = %v, %v; want %v, %v", tt.a, tt.b.lo, got, ok, tt.a.Mul64(tt.b.lo), want.Cmp(limit) < 0)
+		}
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b          uint128
+		add, sub, mul uint128
+	}{
+		{uint128{0, 2}, uint128{0, 3}, uint128{0, 5}, uint128{}, uint128{0, 6}},
+		{uint128{0, 3}, uint128{0, 2}, uint128{0, 5}, uint128{0, 1}, uint128{0, 6}},
+		{max, uint128{0, 1}, max, uint128{^uint64(0), ^uint64(0) - 1}, max},
+		{max, uint128{0, 2}, max, uint128{^uint64(0), ^uint64(0) - 2}, max},
+		{uint128{1, 0}, uint128{1, 0}, uint128{2, 0}, uint128{}, max},
+		{uint128{0, 0}, max, max, uint128{}, uint128{}},
+		{uint128{1 << 62, 0}, uint128{0, 4}, uint128{1 << 62, 4}, uint128{1 << 62, 0}.Sub(uint128{0, 4}), max},
+	}
+	for _, tt := range tests {
+		if got := tt.a.AddSat(tt.b); got != tt.add {
+			t.Errorf("%v.AddSat(%v) = %v; want %v", tt.a, tt.b, got, tt.add)
+		}
+		if got := tt.a.SubSat(tt.b); got != tt.sub {
+			t.Errorf("%v.SubSat(%v) = %v; want %v", tt.a, tt.b, got, tt.sub)
+		}
+		if got := tt.a.MulSat(tt.b); got != tt.mul {
+			t.Errorf("%v.MulSat(%v) = %v; want %v", tt.a, tt.b, got, tt.mul)
+		}
+	}
+}
+
+func TestNeg(t *testing.T) {
+	tests := []struct {
+		in, want uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 1}, uint128{^uint64(0), ^uint64(0)}},
+		{uint128{^uint64(0), ^uint64(0)}, uint128{0, 1}},
+		{uint128{1, 0}, uint128{^uint64(0), 0}},
+		{uint128{1 << 63, 0}, uint128{1 << 63, 0}},
+		{uint128{0, 1 << 63}, uint128{^uint64(0), 1 << 63}},
+	}
+	for _, tt := range tests {
+		got := tt.in.Neg()
+		if got != tt.want {
+			t.Errorf("%v.Neg() = %v; want %v", tt.in, got, tt.want)
+		}
+		if sum := tt.in.Add(got); !sum.IsZero() {
+			t.Errorf("%v + %v.Neg() = %v; want 0", tt.in, tt.in, sum)
+		}
+	}
+}
+
+func TestMinMaxClampAbsDiff(t *testing.T) {
+	tests := []struct {
+		a, b     uint128
+		min, max uint128
+		absDiff  uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}, uint128{0, 0}, uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 3}, uint128{0, 10}, uint128{0, 3}, uint128{0, 10}, uint128{0, 7}},
+		{uint128{0, 10}, uint128{0, 3}, uint128{0, 3}, uint128{0, 10}, uint128{0, 7}},
+		{uint128{1, 0}, uint128{0, ^uint64(0)}, uint128{0, ^uint64(0)}, uint128{1, 0}, uint128{0, 1}},
+		{uint128{^uint64(0), ^uint64(0)}, uint128{0, 0}, uint128{0, 0}, uint128{^uint64(0), ^uint64(0)}, uint128{^uint64(0), ^uint64(0)}},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Min(tt.b); got != tt.min {
+			t.Errorf("%v.Min(%v) = %v; want %v", tt.a, tt.b, got, tt.min)
+		}
+		if got := tt.a.Max(tt.b); got != tt.max {
+			t.Errorf("%v.Max(%v) = %v; want %v", tt.a, tt.b, got, tt.max)
+		}
+		if got := tt.a.AbsDiff(tt.b); got != tt.absDiff {
+			t.Errorf("%v.AbsDiff(%v) = %v; want %v", tt.a, tt.b, got, tt.absDiff)
+		}
+	}
+
+	lo, hi := uint128{0, 10}, uint128{1, 0}
+	clampTests := []struct {
+		in, want uint128
+	}{
+		{uint128{0, 0}, lo},
+		{uint128{0, 10}, lo},
+		{uint128{0, 11}, uint128{0, 11}},
+		{uint128{1, 0}, hi},
+		{uint128{1, 1}, hi},
+		{uint128{^uint64(0), ^uint64(0)}, hi},
+	}
+	for _, tt := range clampTests {
+		if got := tt.in.Clamp(lo, hi); got != tt.want {
+			t.Errorf("%v.Clamp(%v, %v) = %v; want %v", tt.in, lo, hi, got, tt.want)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b, want uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 0}, uint128{0, 1}, uint128{0, 0}},
+		{uint128{0, 2}, uint128{0, 8}, uint128{0, 5}},
+		{uint128{0, 8}, uint128{0, 2}, uint128{0, 5}},
+		{uint128{1, 0}, uint128{0, 0}, uint128{0, 1 << 63}},
+		{max, max, max},
+		{max, uint128{0, 0}, uint128{1<<63 - 1, ^uint64(0)}},
+		{max, uint128{^uint64(0), ^uint64(0) - 2}, uint128{^uint64(0), ^uint64(0) - 1}},
+		{uint128{0, ^uint64(0)}, uint128{0, ^uint64(0)}, uint128{0, ^uint64(0)}},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Midpoint(tt.b); got != tt.want {
+			t.Errorf("%v.Midpoint(%v) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestExp(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		base, exp uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 0}, uint128{0, 5}},
+		{uint128{0, 1}, max},
+		{uint128{0, 0}, max},
+		{uint128{0, 2}, uint128{0, 10}},
+		{uint128{0, 2}, uint128{0, 127}},
+		{uint128{0, 2}, uint128{0, 128}},
+		{uint128{0, 2}, uint128{0, 129}},
+		{uint128{0, 3}, uint128{0, 80}},
+		{uint128{0, 3}, uint128{0, 81}},
+		{uint128{0, 10}, uint128{0, 38}},
+		{uint128{0, 10}, uint128{0, 39}},
+		{uint128{0, ^uint64(0)}, uint128{0, 2}},
+		{uint128{0, ^uint64(0)}, uint128{0, 3}},
+		{uint128{1, 0}, uint128{0, 1}},
+		{uint128{1, 0}, uint128{0, 2}},
+		{max, uint128{0, 1}},
+		{max, uint128{0, 2}},
+		{uint128{0, 7}, uint128{0, 1000}},
+	}
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, tt := range tests {
+		want := new(big.Int).Exp(toBig(tt.base), toBig(tt.exp), mod)
+		// Only 0 and 1 have powers this large that fit in 128 bits.
+		wantOK := tt.base.hi == 0 && tt.base.lo <= 1
+		if tt.exp.hi == 0 && tt.exp.lo <= 1000 {
+			wantOK = new(big.Int).Exp(toBig(tt.base), toBig(tt.exp), nil).Cmp(mod) < 0
+		}
+		got, ok := tt.base.Exp(tt.exp)
+		if toBig(got).Cmp(want) != 0 || ok != wantOK {
+			t.Errorf("%v.Exp(%v) = %v, %v; want %v, %v", tt.base, tt.exp, got, ok, fromBig(want), wantOK)
+		}
+	}
+}
+
+func TestRoundUpDown(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		u, m     uint128
+		down, up uint128
+		upOK     bool
+	}{
+		{uint128{0, 0}, uint128{0, 8}, uint128{0, 0}, uint128{0, 0}, true},
+		{uint128{0, 13}, uint128{0, 8}, uint128{0, 8}, uint128{0, 16}, true},
+		{uint128{0, 16}, uint128{0, 8}, uint128{0, 16}, uint128{0, 16}, true},
+		{uint128{0, 13}, uint128{0, 5}, uint128{0, 10}, uint128{0, 15}, true},
+		{uint128{0, 13}, uint128{0, 1}, uint128{0, 13}, uint128{0, 13}, true},
+		{uint128{1, 1}, uint128{1, 0}, uint128{1, 0}, uint128{2, 0}, true},
+		{max, uint128{0, 1}, max, max, true},
+		{max, uint128{0, 4096}, max.Sub(uint128{0, 4095}), uint128{}, false},
+		{max, uint128{0, 10}, max.Sub(uint128{0, 5}), uint128{0, 4}, false},
+		{max, max, max, max, true},
+	}
+	for _, tt := range tests {
+		if got := tt.u.RoundDown(tt.m); got != tt.down {
+			t.Errorf("%v.RoundDown(%v) = %v; want %v", tt.u, tt.m, got, tt.down)
+		}
+		if got, ok := tt.u.RoundUp(tt.m); got != tt.up || ok != tt.upOK {
+			t.Errorf("%v.RoundUp(%v) = %v, %v; want %v, %v", tt.u, tt.m, got, ok, tt.up, tt.upOK)
+		}
+	}
+}
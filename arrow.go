@@ -0,0 +1,74 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "errors"
+
+// Arrow and Parquet store a decimal128(precision, scale) value as a
+// 16-byte little-endian two's-complement integer n denoting
+// n * 10^-scale, with at most 38 decimal digits in n.
+
+// arrowMaxUnscaled is 10^38 - 1, the largest decimal128(38, s) integer.
+var arrowMaxUnscaled = pow10tab[38].SubOne()
+
+// ToArrowDecimal returns the Arrow decimal128 encoding of u with the
+// given scale, that is the little-endian two's-complement form of
+// u * 10^scale. A negative scale divides instead, and requires u to be
+// a multiple of 10^-scale. It returns an error if the unscaled integer
+// would have more than 38 digits or u is not representable exactly.
+func (u Uint128) ToArrowDecimal(scale int32) ([16]byte, error) {
+	var (
+		n  Uint128
+		ok bool
+	)
+	switch {
+	case scale >= 0:
+		if scale < int32(len(pow10tab)) {
+			n, ok = u.MulChecked(pow10tab[scale])
+		}
+		ok = ok || u.IsZero()
+	default:
+		r := u
+		if scale > -int32(len(pow10tab)) {
+			n, r = u.QuoRem(pow10tab[-scale])
+		}
+		if !r.IsZero() {
+			return [16]byte{}, errors.New("uint128: value not representable at negative Arrow decimal scale")
+		}
+		ok = true
+	}
+	if !ok || n.Cmp(arrowMaxUnscaled) > 0 {
+		return [16]byte{}, errors.New("uint128: value exceeds Arrow decimal128 precision")
+	}
+	return n.BytesLE(), nil
+}
+
+// FromArrowDecimal returns the value of the Arrow decimal128 b with
+// the given scale, the inverse of ToArrowDecimal. It returns an error
+// if b is negative or has a nonzero fractional part at that scale.
+func FromArrowDecimal(b [16]byte, scale int32) (Uint128, error) {
+	if b[15]&0x80 != 0 {
+		return Uint128{}, errors.New("uint128: negative Arrow decimal")
+	}
+	n := FromBytesLE(b)
+	switch {
+	case n.IsZero() || scale == 0:
+		return n, nil
+	case scale > 0:
+		if scale < int32(len(pow10tab)) {
+			if q, r := n.QuoRem(pow10tab[scale]); r.IsZero() {
+				return q, nil
+			}
+		}
+		return Uint128{}, errors.New("uint128: Arrow decimal has a fractional part")
+	default:
+		if scale > -int32(len(pow10tab)) {
+			if v, ok := n.MulChecked(pow10tab[-scale]); ok {
+				return v, nil
+			}
+		}
+		return Uint128{}, errors.New("uint128: Arrow decimal out of range")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// arrowBytes returns the 16-byte little-endian two's complement of x.
+func arrowBytes(x *big.Int) [16]byte {
+	m := new(big.Int).Lsh(big.NewInt(1), 128)
+	v := new(big.Int).Mod(x, m)
+	var b [16]byte
+	v.FillBytes(b[:])
+	for i := 0; i < 8; i++ {
+		b[i], b[15-i] = b[15-i], b[i]
+	}
+	return b
+}
+
+func TestToArrowDecimal(t *testing.T) {
+	tests := []struct {
+		u     uint128
+		scale int32
+		ok    bool
+	}{
+		{uint128{}, 0, true},
+		{uint128{}, 100, true},
+		{uint128{}, -100, true},
+		{uint128{0, 12345}, 0, true},
+		{uint128{0, 12345}, 2, true},
+		{uint128{0, 12300}, -2, true},
+		{uint128{0, 12345}, -2, false},
+		{uint128{0, 1}, 37, true},
+		{uint128{0, 9}, 37, true},
+		{uint128{0, 10}, 37, false},
+		{uint128{0, 1}, 38, false},
+		{uint128{0, 1}, -39, false},
+		{uint128{0, 1}, -int32(len(pow10tab)), false},
+		{uint128{0, 1}, math.MinInt32, false},
+		{Max, math.MinInt32, false},
+		{uint128{}, math.MinInt32, true},
+		{uint128{}, math.MaxInt32, true},
+		{pow10tab[38].SubOne(), 0, true},
+		{pow10tab[38], 0, false},
+		{pow10tab[38], -1, true},
+		{Max, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.u.ToArrowDecimal(tt.scale)
+		if (err == nil) != tt.ok {
+			t.Errorf("%v.ToArrowDecimal(%d) error = %v; want ok %v", tt.u, tt.scale, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		n := toBig(tt.u)
+		switch {
+		case tt.u.IsZero():
+			// 0 at any scale; avoid computing 10^|scale|.
+		case tt.scale >= 0:
+			n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tt.scale)), nil))
+		default:
+			n.Quo(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(-int64(tt.scale)), nil))
+		}
+		if want := arrowBytes(n); got != want {
+			t.Errorf("%v.ToArrowDecimal(%d) = %x; want %x", tt.u, tt.scale, got, want)
+		}
+		if back, err := FromArrowDecimal(got, tt.scale); err != nil || back != tt.u {
+			t.Errorf("FromArrowDecimal(%x, %d) = %v, %v; want %v", got, tt.scale, back, err, tt.u)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r).Rsh(uint(r.Intn(128)))
+		scale := int32(r.Intn(10))
+		b, err := u.ToArrowDecimal(scale)
+		if err != nil {
+			continue
+		}
+		if back, err := FromArrowDecimal(b, scale); err != nil || back != u {
+			t.Fatalf("FromArrowDecimal(%v.ToArrowDecimal(%d)) = %v, %v", u, scale, back, err)
+		}
+	}
+}
+
+func TestFromArrowDecimal(t *testing.T) {
+	tests := []struct {
+		n     *big.Int
+		scale int32
+		want  uint128
+		ok    bool
+	}{
+		{big.NewInt(0), 5, uint128{}, true},
+		{big.NewInt(12345), 0, uint128{0, 12345}, true},
+		{big.NewInt(12300), 2, uint128{0, 123}, true},
+		{big.NewInt(12345), 2, uint128{}, false},
+		{big.NewInt(123), -2, uint128{0, 12300}, true},
+		{big.NewInt(-1), 0, uint128{}, false},
+		{big.NewInt(-100), 2, uint128{}, false},
+		{big.NewInt(1), 39, uint128{}, false},
+		{big.NewInt(1), -38, pow10tab[38], true},
+		{big.NewInt(1), -39, uint128{}, false},
+		{big.NewInt(1), -int32(len(pow10tab)), uint128{}, false},
+		{big.NewInt(1), math.MinInt32, uint128{}, false},
+		{big.NewInt(0), math.MinInt32, uint128{}, true},
+		{big.NewInt(1), math.MaxInt32, uint128{}, false},
+		{big.NewInt(4), -38, uint128{}, false},
+	}
+	for _, tt := range tests {
+		b := arrowBytes(tt.n)
+		got, err := FromArrowDecimal(b, tt.scale)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("FromArrowDecimal(%v, %d) = %v, %v; want %v, ok %v", tt.n, tt.scale, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "errors"
+
+// asn1TagInteger is the DER identifier octet of a universal INTEGER.
+const asn1TagInteger = 0x02
+
+// MarshalASN1 returns the DER encoding of u as an ASN.1 INTEGER, as
+// used for certificate serial numbers and SNMP Counter64 values. The
+// content is the minimal two's-complement form, so a 0x00 byte is
+// prepended when the most significant bit of the first byte is set,
+// and zero is encoded as a single 0x00 byte.
+func (u Uint128) MarshalASN1() ([]byte, error) {
+	b := u.Bytes()
+	content := b[min(u.LeadingZeros()/8, 15):]
+	buf := make([]byte, 0, 3+len(content))
+	buf = append(buf, asn1TagInteger, byte(len(content)))
+	if content[0]&0x80 != 0 {
+		buf[1]++
+		buf = append(buf, 0)
+	}
+	return append(buf, content...), nil
+}
+
+// UnmarshalASN1 parses a DER-encoded ASN.1 INTEGER, as produced by
+// MarshalASN1. It rejects non-minimal encodings, negative values,
+// values of more than 128 bits and trailing data.
+func (u *Uint128) UnmarshalASN1(data []byte) error {
+	if len(data) < 2 || data[0] != asn1TagInteger {
+		return errors.New("uint128: UnmarshalASN1: not an INTEGER")
+	}
+	// Contents of at most 17 bytes always use the short length form.
+	n := int(data[1])
+	content := data[2:]
+	if n >= 0x80 || n == 0 || len(content) < n {
+		return errors.New("uint128: UnmarshalASN1: invalid length")
+	}
+	if len(content) > n {
+		return errors.New("uint128: UnmarshalASN1: trailing data")
+	}
+	if content[0]&0x80 != 0 {
+		return errors.New("uint128: UnmarshalASN1: negative integer")
+	}
+	if n > 1 && content[0] == 0 && content[1]&0x80 == 0 {
+		return errors.New("uint128: UnmarshalASN1: integer not minimally encoded")
+	}
+	if content[0] == 0 {
+		content = content[1:]
+	}
+	v, err := SetBytes(content)
+	if err != nil {
+		return errors.New("uint128: UnmarshalASN1: integer overflows 128 bits")
+	}
+	*u = v
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestMarshalASN1(t *testing.T) {
+	tests := []uint128{
+		{}, {0, 1}, {0, 0x7f}, {0, 0x80}, {0, 0xff}, {0, 0x100},
+		{0, 1<<63 - 1}, {0, 1 << 63}, {1, 0}, {1 << 63, 0}, Max,
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		tests = append(tests, randUint128(r).Rsh(uint(r.Intn(128))))
+	}
+	for _, u := range tests {
+		got, err := u.MarshalASN1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := asn1.Marshal(toBig(u))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%v.MarshalASN1() = %x; want %x", u, got, want)
+		}
+		var v uint128
+		if err := v.UnmarshalASN1(got); err != nil || v != u {
+			t.Errorf("UnmarshalASN1(%x) = %v, %v; want %v", got, v, err, u)
+		}
+	}
+}
+
+func TestUnmarshalASN1(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint128
+		ok   bool
+	}{
+		{"020100", uint128{}, true},
+		{"02017f", uint128{0, 0x7f}, true},
+		{"02020080", uint128{0, 0x80}, true},
+		{"021100ffffffffffffffffffffffffffffffff", Max, true},
+
+		{"", uint128{}, false},
+		{"0201", uint128{}, false},                                     // short content
+		{"0200", uint128{}, false},                                     // empty content
+		{"040100", uint128{}, false},                                   // OCTET STRING
+		{"02810100", uint128{}, false},                                 // long length form
+		{"02020001", uint128{}, false},                                 // non-minimal
+		{"0202ff80", uint128{}, false},                                 // non-minimal
+		{"0201ff", uint128{}, false},                                   // -1
+		{"02020100ff", uint128{}, false},                               // trailing data
+		{"0211010000000000000000000000000000000000", uint128{}, false}, // 2^128
+	}
+	for _, tt := range tests {
+		b, err := hex.DecodeString(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := uint128{0, 7}
+		err = got.UnmarshalASN1(b)
+		if tt.ok && (err != nil || got != tt.want) {
+			t.Errorf("UnmarshalASN1(%s) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+		if !tt.ok && (err == nil || got != (uint128{0, 7})) {
+			t.Errorf("UnmarshalASN1(%s) = %v, %v; want error", tt.in, got, err)
+		}
+		// Whatever UnmarshalASN1 accepts, encoding/asn1 accepts too.
+		if err == nil {
+			var n *big.Int
+			if rest, err := asn1.Unmarshal(b, &n); err != nil || len(rest) != 0 || toBig(got).Cmp(n) != 0 {
+				t.Errorf("asn1.Unmarshal(%s) = %v, %v", tt.in, n, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// A Barrett is a reducer precomputed for a fixed modulus, which
+// reduces 256-bit values such as the products returned by MulFull
+// using two 128-bit multiplications instead of a division.
+//
+// Unlike Montgomery it works with any non-zero modulus, even or odd,
+// and needs no conversion in and out of a special form. It uses the
+// normalized reciprocal formulation of Möller and Granlund, which is
+// Barrett reduction with a one-limb quotient in base 2^128.
+//
+// The zero Barrett is not usable; create one with NewBarrett.
+type Barrett struct {
+	m     Uint128 // the modulus
+	d     Uint128 // m<<shift, with its top bit set
+	shift uint
+	v     Uint128 // floor((2^256-1)/d) - 2^128
+}
+
+// NewBarrett returns the reducer for the modulus m.
+// Like division by zero, it panics if m is zero.
+func NewBarrett(m Uint128) Barrett {
+	s := uint(128 - m.Len())
+	d := m.Lsh(s)
+	// (2^256-1) - 2^128*d == (^d, 2^128-1), and ^d < d.
+	v, _ := NewDivisor(d).quoRem256(d.Not(), Max)
+	return Barrett{m: m, d: d, shift: s, v: v}
+}
+
+// Modulus returns the modulus of the reducer.
+func (b Barrett) Modulus() Uint128 { return b.m }
+
+// Reduce returns the 256-bit value (hi, lo) modulo b.Modulus().
+// It is cheapest when hi is already below the modulus, as it is for
+// products of two reduced values.
+func (b Barrett) Reduce(hi, lo Uint128) Uint128 {
+	if hi.Cmp(b.m) >= 0 {
+		hi = b.Reduce(Uint128{}, hi)
+	}
+	// Normalize. Since hi < m, (hi, lo)<<shift still fits in 256 bits
+	// and its upper half u1 is below d.
+	u1 := hi.Lsh(b.shift).Or(lo.Rsh(128 - b.shift))
+	u0 := lo.Lsh(b.shift)
+
+	q1, q0 := b.v.MulFull(u1)
+	q0, c := q0.AddCarry(u0, 0)
+	q1, _ = q1.AddCarry(u1, c)
+	q1 = q1.AddOne()
+	r := u0.Sub(q1.Mul(b.d))
+	if r.Cmp(q0) > 0 {
+		r = r.Add(b.d)
+	}
+	if r.Cmp(b.d) >= 0 {
+		r = r.Sub(b.d)
+	}
+	return r.Rsh(b.shift)
+}
+
+// MulMod returns (x * y) mod b.Modulus().
+func (b Barrett) MulMod(x, y Uint128) Uint128 {
+	return b.Reduce(x.MulFull(y))
+}
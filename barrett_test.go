@@ -0,0 +1,55 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestBarrett(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	moduli := []uint128{
+		{0, 1}, {0, 2}, {0, 3}, {0, 1 << 40}, {0, 1000000007}, {0, ^uint64(0)},
+		{1, 0}, {1, 1}, {1 << 63, 0}, {^uint64(0), ^uint64(0) - 158}, max,
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for len(moduli) < 50 {
+		if m := randUint128(rnd); !m.IsZero() {
+			moduli = append(moduli, m)
+		}
+	}
+	for _, m := range moduli {
+		b := NewBarrett(m)
+		if b.Modulus() != m {
+			t.Errorf("NewBarrett(%v).Modulus() = %v", m, b.Modulus())
+		}
+		inputs := [][2]uint128{{}, {{}, max}, {max, max}, {m.SubOne(), max}, {m, {}}}
+		for i := 0; i < 20; i++ {
+			inputs = append(inputs, [2]uint128{randUint128(rnd), randUint128(rnd)})
+		}
+		for _, in := range inputs {
+			x := new(big.Int).Lsh(toBig(in[0]), 128)
+			x.Or(x, toBig(in[1]))
+			want := x.Mod(x, toBig(m))
+			if got := b.Reduce(in[0], in[1]); toBig(got).Cmp(want) != 0 {
+				t.Errorf("NewBarrett(%v).Reduce(%v, %v) = %v; want %v", m, in[0], in[1], got, fromBig(want))
+			}
+			if got, want := b.MulMod(in[0], in[1]), in[0].ModMul(in[1], m); got != want {
+				t.Errorf("NewBarrett(%v).MulMod(%v, %v) = %v; want %v", m, in[0], in[1], got, want)
+			}
+		}
+	}
+}
+
+func TestNewBarrettZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewBarrett(0) did not panic")
+		}
+	}()
+	NewBarrett(uint128{})
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+)
+
+// The encodings below all encode the 16-byte big-endian form of a Uint128
+// (see Bytes), so that values with the same bytes encode identically
+// regardless of how they were produced. Their decoders reject input
+// that does not decode to exactly 16 bytes.
+
+var base32NoPad = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EncodeBase64 returns the unpadded URL-safe base64 encoding (RFC 4648
+// section 5) of u's 16 bytes, a 22-character string.
+func (u Uint128) EncodeBase64() string {
+	b := u.Bytes()
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// DecodeBase64 decodes a string produced by EncodeBase64.
+func DecodeBase64(s string) (Uint128, error) {
+	return decode16(base64.RawURLEncoding.Strict(), s, "base64")
+}
+
+// EncodeBase32 returns the unpadded standard base32 encoding (RFC 4648
+// section 6) of u's 16 bytes, a 26-character string.
+func (u Uint128) EncodeBase32() string {
+	b := u.Bytes()
+	return base32NoPad.EncodeToString(b[:])
+}
+
+// DecodeBase32 decodes a string produced by EncodeBase32.
+func DecodeBase32(s string) (Uint128, error) {
+	u, err := decode16(base32NoPad, s, "base32")
+	// Unlike base64, base32 has no strict mode rejecting nonzero
+	// padding bits in the last character.
+	if err == nil && u.EncodeBase32() != s {
+		return Uint128{}, errors.New("uint128: non-canonical base32 encoding")
+	}
+	return u, err
+}
+
+// decoder is implemented by *base64.Encoding and *base32.Encoding.
+type decoder interface {
+	DecodedLen(n int) int
+	Decode(dst, src []byte) (n int, err error)
+}
+
+func decode16(enc decoder, s, name string) (Uint128, error) {
+	if enc.DecodedLen(len(s)) != 16 {
+		return Uint128{}, errors.New("uint128: invalid " + name + " length")
+	}
+	var b [16]byte
+	if _, err := enc.Decode(b[:], []byte(s)); err != nil {
+		return Uint128{}, err
+	}
+	return FromBytesBE(b), nil
+}
+
+// base58Alphabet is the Bitcoin base58 alphabet, which omits 0, O, I
+// and l.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// EncodeBase58 returns the Bitcoin-style base58 encoding of u's 16
+// bytes: each leading zero byte is written as '1', followed by the
+// remaining bytes as a base-58 number. The result is at most 22
+// characters long, or 16 for zero.
+func (u Uint128) EncodeBase58() string {
+	var buf [22 + 16]byte
+	i := len(buf)
+	for v := u; !v.IsZero(); {
+		var r uint64
+		v, r = v.Div64(58)
+		i--
+		buf[i] = base58Alphabet[r]
+	}
+	for z := u.LeadingZeros() / 8; z > 0; z-- {
+		i--
+		buf[i] = '1'
+	}
+	return string(buf[i:])
+}
+
+// DecodeBase58 decodes a string produced by EncodeBase58. It rejects
+// non-canonical input, such as a wrong number of leading '1's.
+func DecodeBase58(s string) (Uint128, error) {
+	var u Uint128
+	for i := 0; i < len(s); i++ {
+		d := base58Index(s[i])
+		if d < 0 {
+			return Uint128{}, errors.New("uint128: illegal base58 character")
+		}
+		var ok bool
+		if u, ok = u.Mul64Checked(58); ok {
+			u, ok = u.AddChecked(Uint128{0, uint64(d)})
+		}
+		if !ok {
+			return Uint128{}, errors.New("uint128: base58 value too large")
+		}
+	}
+	if u.EncodeBase58() != s {
+		return Uint128{}, errors.New("uint128: non-canonical base58 encoding")
+	}
+	return u, nil
+}
+
+func base58Index(c byte) int {
+	for i := 0; i < len(base58Alphabet); i++ {
+		if base58Alphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// crockfordAlphabet is Douglas Crockford's base32 alphabet, which
+// omits I, L, O and U; crockfordCheck extends it with the five extra
+// symbols used for the mod-37 check symbol.
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	crockfordCheck    = crockfordAlphabet + "*~$=U"
+)
+
+// EncodeCrockford returns u as a 26-character Crockford base32 number,
+// zero-padded on the left so that all values have the same length.
+// The first character is at most '7'.
+func (u Uint128) EncodeCrockford() string {
+	var buf [26]byte
+	u.putCrockford(&buf)
+	return string(buf[:])
+}
+
+// EncodeCrockfordCheck is like EncodeCrockford but appends Crockford's
+// check symbol, u mod 37, which detects any single wrong or
+// transposed-adjacent character.
+func (u Uint128) EncodeCrockfordCheck() string {
+	var buf [27]byte
+	u.putCrockford((*[26]byte)(buf[:26]))
+	_, r := u.Div64(37)
+	buf[26] = crockfordCheck[r]
+	return string(buf[:])
+}
+
+func (u Uint128) putCrockford(buf *[26]byte) {
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[u.lo&31]
+		u = u.Rsh(5)
+	}
+}
+
+// DecodeCrockford decodes a Crockford base32 number of up to 26
+// symbols, such as one produced by EncodeCrockford. As the encoding
+// prescribes, decoding is case-insensitive, accepts I and L for 1 and
+// O for 0, and ignores hyphens.
+func DecodeCrockford(s string) (Uint128, error) {
+	var u Uint128
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '-' {
+			continue
+		}
+		d := crockfordValue(c)
+		if d < 0 || d >= 32 {
+			return Uint128{}, errors.New("uint128: illegal Crockford base32 character")
+		}
+		if n++; n > 26 || u.hi>>59 != 0 {
+			return Uint128{}, errors.New("uint128: Crockford base32 value too large")
+		}
+		u = u.Lsh(5).Or(Uint128{0, uint64(d)})
+	}
+	if n == 0 {
+		return Uint128{}, errors.New("uint128: empty Crockford base32 string")
+	}
+	return u, nil
+}
+
+// DecodeCrockfordCheck decodes a string produced by
+// EncodeCrockfordCheck, verifying the trailing check symbol.
+func DecodeCrockfordCheck(s string) (Uint128, error) {
+	if s == "" {
+		return Uint128{}, errors.New("uint128: empty Crockford base32 string")
+	}
+	u, err := DecodeCrockford(s[:len(s)-1])
+	if err != nil {
+		return Uint128{}, err
+	}
+	if _, r := u.Div64(37); crockfordValue(s[len(s)-1]) != int(r) {
+		return Uint128{}, errors.New("uint128: Crockford base32 check symbol mismatch")
+	}
+	return u, nil
+}
+
+// crockfordValue returns the value of the Crockford symbol c,
+// including check symbols, or -1.
+func crockfordValue(c byte) int {
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	}
+	for i := 0; i < len(crockfordCheck); i++ {
+		if crockfordCheck[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestBaseEncodings(t *testing.T) {
+	tests := []struct {
+		u             uint128
+		b64, b32, b58 string
+	}{
+		{uint128{}, "AAAAAAAAAAAAAAAAAAAAAA", "AAAAAAAAAAAAAAAAAAAAAAAAAA", "1111111111111111"},
+		{uint128{0, 1}, "AAAAAAAAAAAAAAAAAAAAAQ", "AAAAAAAAAAAAAAAAAAAAAAAAAE", "1111111111111112"},
+		{uint128{0, 57}, "AAAAAAAAAAAAAAAAAAAAOQ", "AAAAAAAAAAAAAAAAAAAAAAAAHE", "111111111111111z"},
+		{uint128{0, 58}, "AAAAAAAAAAAAAAAAAAAAOg", "AAAAAAAAAAAAAAAAAAAAAAAAHI", "11111111111111121"},
+		{Max, "_____________________w", "77777777777777777777777774", "YcVfxkQb6JRzqk5kF2tNLv"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.EncodeBase64(); got != tt.b64 {
+			t.Errorf("%v.EncodeBase64() = %s; want %s", tt.u, got, tt.b64)
+		}
+		if got := tt.u.EncodeBase32(); got != tt.b32 {
+			t.Errorf("%v.EncodeBase32() = %s; want %s", tt.u, got, tt.b32)
+		}
+		if got := tt.u.EncodeBase58(); got != tt.b58 {
+			t.Errorf("%v.EncodeBase58() = %s; want %s", tt.u, got, tt.b58)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		for _, enc := range []struct {
+			name   string
+			s      string
+			decode func(string) (Uint128, error)
+		}{
+			{"base64", u.EncodeBase64(), DecodeBase64},
+			{"base32", u.EncodeBase32(), DecodeBase32},
+			{"base58", u.EncodeBase58(), DecodeBase58},
+		} {
+			if got, err := enc.decode(enc.s); err != nil || got != u {
+				t.Fatalf("%s decode(%q) = %v, %v; want %v", enc.name, enc.s, got, err, u)
+			}
+		}
+	}
+}
+
+func TestBaseDecodeErrors(t *testing.T) {
+	for _, s := range []string{"", "AAAA", "AAAAAAAAAAAAAAAAAAAAAAA", "AAAAAAAAAAAAAAAAAAAAAR", "AAAAAAAAAAAAAAAAAAAAA+", "AAAAAAAAAAAAAAAAAAAA==", "AAAAAAAAAAAAAAAAAAAAAAAAAA"} {
+		if u, err := DecodeBase64(s); err == nil {
+			t.Errorf("DecodeBase64(%q) = %v; want error", s, u)
+		}
+	}
+	for _, s := range []string{"", "AAAA", "AAAAAAAAAAAAAAAAAAAAAAAAAF", "aaaaaaaaaaaaaaaaaaaaaaaaaa", "AAAAAAAAAAAAAAAAAAAAAAAAA1", strings.Repeat("A", 27)} {
+		if u, err := DecodeBase32(s); err == nil {
+			t.Errorf("DecodeBase32(%q) = %v; want error", s, u)
+		}
+	}
+	for _, s := range []string{"", "2", "11111111111111111", "111111111111111", "0", "YcVfxkQb6JRzqk5kF2tNLw", "zzzzzzzzzzzzzzzzzzzzzz"} {
+		if u, err := DecodeBase58(s); err == nil {
+			t.Errorf("DecodeBase58(%q) = %v; want error", s, u)
+		}
+	}
+}
+
+func TestCrockford(t *testing.T) {
+	tests := []struct {
+		u          uint128
+		enc, check string
+	}{
+		{uint128{}, "00000000000000000000000000", "000000000000000000000000000"},
+		{uint128{0, 1}, "00000000000000000000000001", "000000000000000000000000011"},
+		{uint128{0, 36}, "00000000000000000000000014", "00000000000000000000000014U"},
+		{uint128{0, 0x94a}, "000000000000000000000002AA", "000000000000000000000002AAA"},
+		{uint128{1, 0}, "0000000000000G000000000000", "0000000000000G000000000000C"},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, "014D2PF2DBSQQZXQ5TK1V58CGG", "014D2PF2DBSQQZXQ5TK1V58CGGA"},
+		{Max, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", "7ZZZZZZZZZZZZZZZZZZZZZZZZZ*"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.EncodeCrockford(); got != tt.enc {
+			t.Errorf("%v.EncodeCrockford() = %s; want %s", tt.u, got, tt.enc)
+		}
+		if got := tt.u.EncodeCrockfordCheck(); got != tt.check {
+			t.Errorf("%v.EncodeCrockfordCheck() = %s; want %s", tt.u, got, tt.check)
+		}
+		if got, err := DecodeCrockford(tt.enc); err != nil || got != tt.u {
+			t.Errorf("DecodeCrockford(%s) = %v, %v; want %v", tt.enc, got, err, tt.u)
+		}
+		if got, err := DecodeCrockfordCheck(tt.check); err != nil || got != tt.u {
+			t.Errorf("DecodeCrockfordCheck(%s) = %v, %v; want %v", tt.check, got, err, tt.u)
+		}
+	}
+
+	// Lenient decoding of human input.
+	for _, tt := range []struct {
+		s    string
+		want uint128
+	}{
+		{"014d2pf2-dbsqq-zxq5t-k1v58-cgg", uint128{0x0123456789abcdef, 0xfedcba9876543210}},
+		{"oOiIlL", uint128{0, 0x8421}},
+		{"10", uint128{0, 32}},
+	} {
+		if got, err := DecodeCrockford(tt.s); err != nil || got != tt.want {
+			t.Errorf("DecodeCrockford(%q) = %v, %v; want %v", tt.s, got, err, tt.want)
+		}
+	}
+	if got, err := DecodeCrockfordCheck("014d2pf2dbsqqzxq5tk1v58cgga"); err != nil || got != (uint128{0x0123456789abcdef, 0xfedcba9876543210}) {
+		t.Errorf("DecodeCrockfordCheck(lowercase) = %v, %v", got, err)
+	}
+
+	for _, s := range []string{"", "-", "U", "*", "00000000000000000000000000\n", "80000000000000000000000000", "000000000000000000000000000"} {
+		if u, err := DecodeCrockford(s); err == nil {
+			t.Errorf("DecodeCrockford(%q) = %v; want error", s, u)
+		}
+	}
+	for _, s := range []string{"", "0", "000000000000000000000000001", "014D2PF2DBSQQZXQ5TK1V58CGGB", "104D2PF2DBSQQZXQ5TK1V58CGGA"} {
+		if u, err := DecodeCrockfordCheck(s); err == nil {
+			t.Errorf("DecodeCrockfordCheck(%q) = %v; want error", s, u)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		if got, err := DecodeCrockfordCheck(u.EncodeCrockfordCheck()); err != nil || got != u {
+			t.Fatalf("DecodeCrockfordCheck(%s) = %v, %v; want %v", u.EncodeCrockfordCheck(), got, err, u)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "math/bits"
+
+// Lsh returns u << n. Bits shifted out of the top are discarded, so
+// any shift of 128 or more returns 0.
+func (u Uint128) Lsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{u.lo << (n - 64), 0}
+	}
+	return Uint128{u.hi<<n | u.lo>>(64-n), u.lo << n}
+}
+
+// Rsh returns u >> n. Bits shifted out of the bottom are discarded, so
+// any shift of 128 or more returns 0.
+func (u Uint128) Rsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{0, u.hi >> (n - 64)}
+	}
+	return Uint128{u.hi >> n, u.lo>>n | u.hi<<(64-n)}
+}
+
+// RotateLeft returns the value of u rotated left by (k mod 128) bits.
+// To rotate u right by k bits, call u.RotateLeft(-k).
+func (u Uint128) RotateLeft(k int) Uint128 {
+	s := uint(k) & 127
+	if s == 0 {
+		return u
+	}
+	return u.Lsh(s).Or(u.Rsh(128 - s))
+}
+
+// LeadingZeros returns the number of leading zero bits in u;
+// the result is 128 for u == 0.
+func (u Uint128) LeadingZeros() int {
+	if u.hi != 0 {
+		return bits.LeadingZeros64(u.hi)
+	}
+	return 64 + bits.LeadingZeros64(u.lo)
+}
+
+// TrailingZeros returns the number of trailing zero bits in u;
+// the result is 128 for u == 0.
+func (u Uint128) TrailingZeros() int {
+	if u.lo != 0 {
+		return bits.TrailingZeros64(u.lo)
+	}
+	return 64 + bits.TrailingZeros64(u.hi)
+}
+
+// OnesCount returns the number of one bits ("population count") in u.
+func (u Uint128) OnesCount() int {
+	return bits.OnesCount64(u.hi) + bits.OnesCount64(u.lo)
+}
+
+// Len returns the minimum number of bits required to represent u;
+// the result is 0 for u == 0.
+func (u Uint128) Len() int {
+	return 128 - u.LeadingZeros()
+}
+
+// bitMask returns the mask selecting the given bit, numbered from the
+// most significant bit as described on Uint128, or 0 if bit >= 128.
+func bitMask(bit uint8) Uint128 {
+	return Uint128{1 << 63, 0}.Rsh(uint(bit))
+}
+
+// Bit returns the value (0 or 1) of the given bit of u, where bit 0 is
+// the most significant bit. It returns 0 for bit >= 128.
+func (u Uint128) Bit(bit uint8) uint {
+	if u.And(bitMask(bit)).IsZero() {
+		return 0
+	}
+	return 1
+}
+
+// SetBit returns a copy of u with the given bit set, where bit 0 is
+// the most significant bit. For bit >= 128 it returns u unchanged.
+func (u Uint128) SetBit(bit uint8) Uint128 {
+	return u.Or(bitMask(bit))
+}
+
+// ClearBit returns a copy of u with the given bit cleared, where bit 0
+// is the most significant bit. For bit >= 128 it returns u unchanged.
+func (u Uint128) ClearBit(bit uint8) Uint128 {
+	return u.And(bitMask(bit).Not())
+}
+
+// ToggleBit returns a copy of u with the given bit inverted, where bit
+// 0 is the most significant bit. For bit >= 128 it returns u unchanged.
+func (u Uint128) ToggleBit(bit uint8) Uint128 {
+	return u.Xor(bitMask(bit))
+}
+
+// Reverse returns the value of u with its bits in reversed order.
+func (u Uint128) Reverse() Uint128 {
+	return Uint128{bits.Reverse64(u.lo), bits.Reverse64(u.hi)}
+}
+
+// ReverseBytes returns the value of u with its 16 bytes in reversed
+// order, converting between big- and little-endian interpretations.
+func (u Uint128) ReverseBytes() Uint128 {
+	return Uint128{bits.ReverseBytes64(u.lo), bits.ReverseBytes64(u.hi)}
+}
+
+// ExtractBits returns the bits of u selected by mask, packed into the
+// low-order bits of the result in the same order, like the x86 PEXT
+// instruction: the least significant selected bit of u becomes the
+// least significant bit of the result.
+func (u Uint128) ExtractBits(mask Uint128) Uint128 {
+	var r Uint128
+	k := uint(0)
+	for m := mask; !m.IsZero(); m = m.And(m.SubOne()) {
+		if low := m.And(m.Neg()); !u.And(low).IsZero() {
+			r = r.Or(Uint128{0, 1}.Lsh(k))
+		}
+		k++
+	}
+	return r
+}
+
+// DepositBits scatters the low-order bits of u to the positions
+// selected by mask, like the x86 PDEP instruction: the least
+// significant bit of u goes to the least significant set bit of mask.
+// It is the inverse of ExtractBits for bits covered by mask.
+func (u Uint128) DepositBits(mask Uint128) Uint128 {
+	var r Uint128
+	for m := mask; !m.IsZero(); m = m.And(m.SubOne()) {
+		if u.lo&1 != 0 {
+			r = r.Or(m.And(m.Neg()))
+		}
+		u = u.Rsh(1)
+	}
+	return r
+}
+
+// ExtractRange returns the bit field of u spanning bits from through
+// to inclusive, where bit 0 is the most significant bit, shifted down
+// so that bit to lands in the least significant position.
+// It panics unless from <= to < 128.
+func (u Uint128) ExtractRange(from, to uint8) Uint128 {
+	w, s := fieldWidth(from, to)
+	return u.Rsh(s).And(LowMask(w))
+}
+
+// InsertRange returns a copy of u with the bit field spanning bits from
+// through to inclusive replaced by the low-order bits of v, where bit 0
+// is the most significant bit. Bits of v that do not fit the field are
+// ignored. It panics unless from <= to < 128.
+func (u Uint128) InsertRange(from, to uint8, v Uint128) Uint128 {
+	w, s := fieldWidth(from, to)
+	field := LowMask(w).Lsh(s)
+	return u.And(field.Not()).Or(v.Lsh(s).And(field))
+}
+
+// fieldWidth validates the bit field [from, to] and returns its width
+// and the shift that aligns it to the least significant bit.
+func fieldWidth(from, to uint8) (w int, shift uint) {
+	if from > to || to > 127 {
+		panic("uint128: invalid bit range")
+	}
+	return int(to-from) + 1, uint(127 - to)
+}
+
+// IsPowerOfTwo reports whether u is a power of two.
+func (u Uint128) IsPowerOfTwo() bool {
+	return !u.IsZero() && u.And(u.SubOne()).IsZero()
+}
+
+// NextPowerOfTwo returns the smallest power of two greater than or
+// equal to u; for u == 0 that is 1. If the result does not fit in 128
+// bits, that is if u > 2^127, it returns 0 and ok == false.
+func (u Uint128) NextPowerOfTwo() (p Uint128, ok bool) {
+	if u.hi == 0 && u.lo <= 1 {
+		return Uint128{0, 1}, true
+	}
+	n := u.SubOne().Len()
+	if n == 128 {
+		return Uint128{}, false
+	}
+	return Uint128{0, 1}.Lsh(uint(n)), true
+}
+
+// ToGray returns the reflected binary Gray code of u.
+func (u Uint128) ToGray() Uint128 {
+	return u.Xor(u.Rsh(1))
+}
+
+// FromGray returns the value whose Gray code is u; it is the inverse
+// of ToGray.
+func (u Uint128) FromGray() Uint128 {
+	// Each output bit is the XOR of all input bits at or above it.
+	for s := uint(1); s < 128; s <<= 1 {
+		u = u.Xor(u.Rsh(s))
+	}
+	return u
+}
+
+// SetBitRange returns a copy of u with bits from through to inclusive
+// set, where bit 0 is the most significant bit.
+// It panics unless from <= to < 128.
+func (u Uint128) SetBitRange(from, to uint8) Uint128 {
+	return u.Or(Mask(from, to))
+}
+
+// ClearBitRange returns a copy of u with bits from through to inclusive
+// cleared, where bit 0 is the most significant bit.
+// It panics unless from <= to < 128.
+func (u Uint128) ClearBitRange(from, to uint8) Uint128 {
+	return u.And(Mask(from, to).Not())
+}
+
+// LshOut returns u << n together with the bits shifted out of the top.
+// Viewing the shift as a 256-bit operation on (0, u), out is the upper
+// half of the result and r the lower half, so the bits of out are in
+// their natural position for OR-ing into the next more significant
+// limb of a wider shift.
+func (u Uint128) LshOut(n uint) (r, out Uint128) {
+	switch {
+	case n == 0:
+		return u, Uint128{}
+	case n < 128:
+		return u.Lsh(n), u.Rsh(128 - n)
+	}
+	return Uint128{}, u.Lsh(n - 128)
+}
+
+// RshOut returns u >> n together with the bits shifted out of the
+// bottom. Viewing the shift as a 256-bit operation on (u, 0), r is the
+// upper half of the result and out the lower half, so the bits of out
+// are in their natural position for OR-ing into the next less
+// significant limb of a wider shift.
+func (u Uint128) RshOut(n uint) (r, out Uint128) {
+	switch {
+	case n == 0:
+		return u, Uint128{}
+	case n < 128:
+		return u.Rsh(n), u.Lsh(128 - n)
+	}
+	return Uint128{}, u.Rsh(n - 128)
+}
+
+// Rank returns the number of set bits of u before bit i, that is among
+// bits 0 through i-1, where bit 0 is the most significant bit.
+// Values of i outside [0, 128] are clamped to that range.
+func (u Uint128) Rank(i int) int {
+	return u.And(Mask6(i)).OnesCount()
+}
+
+// Select returns the number of the set bit of u with rank k, that is
+// the (k+1)th set bit counting from bit 0, the most significant bit.
+// It returns -1 if u has k or fewer set bits. For every set bit i,
+// u.Select(u.Rank(i)) == i.
+func (u Uint128) Select(k int) int {
+	if k < 0 {
+		return -1
+	}
+	x, base := u.hi, 0
+	if c := bits.OnesCount64(u.hi); k >= c {
+		x, base, k = u.lo, 64, k-c
+	}
+	if k >= bits.OnesCount64(x) {
+		return -1
+	}
+	for ; k > 0; k-- {
+		x &^= 1 << 63 >> bits.LeadingZeros64(x)
+	}
+	return base + bits.LeadingZeros64(x)
+}
@@ -0,0 +1,417 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestShifts(t *testing.T) {
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, u := range []uint128{
+		{0, 0}, {0, 1}, {1, 0}, {0, 1 << 63}, {1 << 63, 0},
+		{0x0123456789abcdef, 0xfedcba9876543210}, {^uint64(0), ^uint64(0)},
+	} {
+		for _, n := range []uint{0, 1, 3, 31, 63, 64, 65, 100, 127, 128, 129, 200, ^uint(0)} {
+			bn := n
+			if bn > 1000 {
+				bn = 1000 // keep math/big from allocating huge values
+			}
+			want := new(big.Int).Lsh(toBig(u), bn)
+			want.Mod(want, mod)
+			if got := u.Lsh(n); toBig(got).Cmp(want) != 0 {
+				t.Errorf("%v.Lsh(%d) = %v; want %v", u, n, got, fromBig(want))
+			}
+			want = new(big.Int).Rsh(toBig(u), bn)
+			if got := u.Rsh(n); toBig(got).Cmp(want) != 0 {
+				t.Errorf("%v.Rsh(%d) = %v; want %v", u, n, got, fromBig(want))
+			}
+		}
+	}
+}
+
+func TestRotateLeft(t *testing.T) {
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	tests := []struct {
+		k    int
+		want uint128
+	}{
+		{0, u},
+		{128, u},
+		{-128, u},
+		{64, uint128{u.lo, u.hi}},
+		{-64, uint128{u.lo, u.hi}},
+		{4, uint128{0x123456789abcdeff, 0xedcba98765432100}},
+		{-4, uint128{0x00123456789abcde, 0xffedcba987654321}},
+		{132, uint128{0x123456789abcdeff, 0xedcba98765432100}},
+		{124, uint128{0x00123456789abcde, 0xffedcba987654321}},
+	}
+	for _, tt := range tests {
+		if got := u.RotateLeft(tt.k); got != tt.want {
+			t.Errorf("%v.RotateLeft(%d) = %v; want %v", u, tt.k, got, tt.want)
+		}
+	}
+	for k := -130; k <= 130; k++ {
+		if got := u.RotateLeft(k).RotateLeft(-k); got != u {
+			t.Errorf("%v.RotateLeft(%d).RotateLeft(%d) = %v", u, k, -k, got)
+		}
+	}
+}
+
+func TestBitCounts(t *testing.T) {
+	tests := []struct {
+		u                    uint128
+		lz, tz, ones, length int
+	}{
+		{uint128{0, 0}, 128, 128, 0, 0},
+		{uint128{0, 1}, 127, 0, 1, 1},
+		{uint128{0, 1 << 63}, 64, 63, 1, 64},
+		{uint128{1, 0}, 63, 64, 1, 65},
+		{uint128{1 << 63, 0}, 0, 127, 1, 128},
+		{uint128{0, ^uint64(0)}, 64, 0, 64, 64},
+		{uint128{^uint64(0), 0}, 0, 64, 64, 128},
+		{uint128{^uint64(0), ^uint64(0)}, 0, 0, 128, 128},
+		{uint128{0x00f0, 0x0f00}, 56, 8, 8, 72},
+	}
+	for _, tt := range tests {
+		if got := tt.u.LeadingZeros(); got != tt.lz {
+			t.Errorf("%v.LeadingZeros() = %d; want %d", tt.u, got, tt.lz)
+		}
+		if got := tt.u.TrailingZeros(); got != tt.tz {
+			t.Errorf("%v.TrailingZeros() = %d; want %d", tt.u, got, tt.tz)
+		}
+		if got := tt.u.OnesCount(); got != tt.ones {
+			t.Errorf("%v.OnesCount() = %d; want %d", tt.u, got, tt.ones)
+		}
+		if got := tt.u.Len(); got != tt.length {
+			t.Errorf("%v.Len() = %d; want %d", tt.u, got, tt.length)
+		}
+	}
+}
+
+func TestSingleBit(t *testing.T) {
+	tests := []struct {
+		bit  uint8
+		mask uint128
+	}{
+		{0, uint128{1 << 63, 0}},
+		{1, uint128{1 << 62, 0}},
+		{63, uint128{1, 0}},
+		{64, uint128{0, 1 << 63}},
+		{127, uint128{0, 1}},
+		{128, uint128{0, 0}},
+		{255, uint128{0, 0}},
+	}
+	ones := uint128{^uint64(0), ^uint64(0)}
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	for _, tt := range tests {
+		var zero uint128
+		if got := zero.SetBit(tt.bit); got != tt.mask {
+			t.Errorf("0.SetBit(%d) = %v; want %v", tt.bit, got, tt.mask)
+		}
+		if got := ones.ClearBit(tt.bit); got != tt.mask.Not() {
+			t.Errorf("ones.ClearBit(%d) = %v; want %v", tt.bit, got, tt.mask.Not())
+		}
+		if got := u.ToggleBit(tt.bit); got != u.Xor(tt.mask) {
+			t.Errorf("%v.ToggleBit(%d) = %v; want %v", u, tt.bit, got, u.Xor(tt.mask))
+		}
+		want := uint(1)
+		if tt.mask.IsZero() {
+			want = 0
+		}
+		if got := ones.Bit(tt.bit); got != want {
+			t.Errorf("ones.Bit(%d) = %d; want %d", tt.bit, got, want)
+		}
+		if got := zero.Bit(tt.bit); got != 0 {
+			t.Errorf("0.Bit(%d) = %d; want 0", tt.bit, got)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tests := []struct {
+		in, want uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 1}, uint128{1 << 63, 0}},
+		{uint128{1, 0}, uint128{0, 1 << 63}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x084c2a6e195d3b7f, 0xf7b3d591e6a2c480}},
+		{uint128{^uint64(0), 0}, uint128{0, ^uint64(0)}},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Reverse(); got != tt.want {
+			t.Errorf("%v.Reverse() = %#x; want %#x", tt.in, got, tt.want)
+		}
+		if got := tt.want.Reverse(); got != tt.in {
+			t.Errorf("%v.Reverse() = %#x; want %#x", tt.want, got, tt.in)
+		}
+	}
+	for i := uint8(0); i < 128; i++ {
+		if got, want := bitMask(i).Reverse(), bitMask(127-i); got != want {
+			t.Errorf("bit %d reversed = %#x; want %#x", i, got, want)
+		}
+	}
+}
+
+func TestReverseBytes(t *testing.T) {
+	tests := []struct {
+		in, want uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 1}, uint128{1 << 56, 0}},
+		{uint128{0x0001020304050607, 0x08090a0b0c0d0e0f}, uint128{0x0f0e0d0c0b0a0908, 0x0706050403020100}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x1032547698badcfe, 0xefcdab8967452301}},
+	}
+	for _, tt := range tests {
+		if got := tt.in.ReverseBytes(); got != tt.want {
+			t.Errorf("%v.ReverseBytes() = %#x; want %#x", tt.in, got, tt.want)
+		}
+		if got := tt.want.ReverseBytes(); got != tt.in {
+			t.Errorf("%v.ReverseBytes() = %#x; want %#x", tt.want, got, tt.in)
+		}
+	}
+}
+
+func TestExtractDepositBits(t *testing.T) {
+	ones := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		u, mask, extracted uint128
+	}{
+		{uint128{0, 0}, ones, uint128{0, 0}},
+		{ones, uint128{0, 0}, uint128{0, 0}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, ones, uint128{0x0123456789abcdef, 0xfedcba9876543210}},
+		{uint128{0, 0b1011_0110}, uint128{0, 0b1111_0000}, uint128{0, 0b1011}},
+		{uint128{0, 0b1011_0110}, uint128{0, 0b0101_0101}, uint128{0, 0b0110}},
+		{uint128{1 << 63, 1}, uint128{1 << 63, 1}, uint128{0, 0b11}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{^uint64(0), 0}, uint128{0, 0x0123456789abcdef}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0xf, 0xf << 60}, uint128{0, 0xff}},
+	}
+	for _, tt := range tests {
+		got := tt.u.ExtractBits(tt.mask)
+		if got != tt.extracted {
+			t.Errorf("%v.ExtractBits(%v) = %#x; want %#x", tt.u, tt.mask, got, tt.extracted)
+		}
+		if dep := got.DepositBits(tt.mask); dep != tt.u.And(tt.mask) {
+			t.Errorf("%v.DepositBits(%v) = %#x; want %#x", got, tt.mask, dep, tt.u.And(tt.mask))
+		}
+	}
+	if got := ones.DepositBits(uint128{0, 0b1010}); got != (uint128{0, 0b1010}) {
+		t.Errorf("ones.DepositBits(0b1010) = %#b; want 0b1010", got)
+	}
+	if got := (uint128{0, 0b01}).DepositBits(uint128{1, 1}); got != (uint128{0, 1}) {
+		t.Errorf("1.DepositBits({1, 1}) = %v; want {0, 1}", got)
+	}
+}
+
+func TestExtractInsertRange(t *testing.T) {
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	tests := []struct {
+		from, to uint8
+		field    uint128
+	}{
+		{0, 127, u},
+		{0, 3, uint128{0, 0x0}},
+		{4, 7, uint128{0, 0x1}},
+		{0, 63, uint128{0, 0x0123456789abcdef}},
+		{64, 127, uint128{0, 0xfedcba9876543210}},
+		{60, 67, uint128{0, 0xff}},
+		{124, 127, uint128{0, 0x0}},
+		{120, 127, uint128{0, 0x10}},
+		{8, 8, uint128{0, 0}},
+		{7, 7, uint128{0, 1}},
+		{32, 95, uint128{0, 0x89abcdeffedcba98}},
+		{1, 127, uint128{0x0123456789abcdef, 0xfedcba9876543210}},
+	}
+	for _, tt := range tests {
+		if got := u.ExtractRange(tt.from, tt.to); got != tt.field {
+			t.Errorf("%v.ExtractRange(%d, %d) = %#x; want %#x", u, tt.from, tt.to, got, tt.field)
+		}
+		if got := u.InsertRange(tt.from, tt.to, tt.field); got != u {
+			t.Errorf("%v.InsertRange(%d, %d, %#x) = %#x; want unchanged", u, tt.from, tt.to, tt.field, got)
+		}
+		// Inserting all ones must set exactly the field and nothing else.
+		ones := uint128{^uint64(0), ^uint64(0)}
+		want := uint128{}.BitsSetFrom(tt.from).BitsClearedFrom(tt.to + 1)
+		if got := (uint128{}).InsertRange(tt.from, tt.to, ones); got != want {
+			t.Errorf("0.InsertRange(%d, %d, ones) = %#x; want %#x", tt.from, tt.to, got, want)
+		}
+	}
+
+	for _, r := range [][2]uint8{{5, 4}, {0, 128}, {200, 255}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ExtractRange(%d, %d) did not panic", r[0], r[1])
+				}
+			}()
+			u.ExtractRange(r[0], r[1])
+		}()
+	}
+}
+
+func TestPowerOfTwo(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		u      uint128
+		isPow  bool
+		next   uint128
+		nextOK bool
+	}{
+		{uint128{0, 0}, false, uint128{0, 1}, true},
+		{uint128{0, 1}, true, uint128{0, 1}, true},
+		{uint128{0, 2}, true, uint128{0, 2}, true},
+		{uint128{0, 3}, false, uint128{0, 4}, true},
+		{uint128{0, 1<<63 + 1}, false, uint128{1, 0}, true},
+		{uint128{1, 0}, true, uint128{1, 0}, true},
+		{uint128{1, 1}, false, uint128{2, 0}, true},
+		{uint128{1 << 63, 0}, true, uint128{1 << 63, 0}, true},
+		{uint128{1 << 63, 1}, false, uint128{}, false},
+		{max, false, uint128{}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.u.IsPowerOfTwo(); got != tt.isPow {
+			t.Errorf("%v.IsPowerOfTwo() = %v; want %v", tt.u, got, tt.isPow)
+		}
+		if got, ok := tt.u.NextPowerOfTwo(); got != tt.next || ok != tt.nextOK {
+			t.Errorf("%v.NextPowerOfTwo() = %v, %v; want %v, %v", tt.u, got, ok, tt.next, tt.nextOK)
+		}
+	}
+}
+
+func TestGray(t *testing.T) {
+	tests := []struct {
+		u, gray uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 1}, uint128{0, 1}},
+		{uint128{0, 2}, uint128{0, 3}},
+		{uint128{0, 3}, uint128{0, 2}},
+		{uint128{0, 7}, uint128{0, 4}},
+		{uint128{1, 0}, uint128{1, 1 << 63}},
+		{uint128{^uint64(0), ^uint64(0)}, uint128{1 << 63, 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.u.ToGray(); got != tt.gray {
+			t.Errorf("%v.ToGray() = %#x; want %#x", tt.u, got, tt.gray)
+		}
+		if got := tt.gray.FromGray(); got != tt.u {
+			t.Errorf("%v.FromGray() = %#x; want %#x", tt.gray, got, tt.u)
+		}
+	}
+	// Consecutive values differ in exactly one bit of their Gray codes.
+	u := uint128{0x0123456789abcdef, 0xfffffffffffffff0}
+	for i := 0; i < 64; i++ {
+		next := u.AddOne()
+		if d := u.ToGray().Xor(next.ToGray()).OnesCount(); d != 1 {
+			t.Errorf("Gray codes of %v and %v differ in %d bits", u, next, d)
+		}
+		if got := u.ToGray().FromGray(); got != u {
+			t.Errorf("%v.ToGray().FromGray() = %v", u, got)
+		}
+		u = next
+	}
+}
+
+func TestSetClearBitRange(t *testing.T) {
+	ones := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		from, to uint8
+		set      uint128
+	}{
+		{0, 0, uint128{1 << 63, 0}},
+		{127, 127, uint128{0, 1}},
+		{0, 127, ones},
+		{62, 65, uint128{3, 3 << 62}},
+		{8, 15, uint128{0xff << 48, 0}},
+	}
+	for _, tt := range tests {
+		if got := (uint128{}).SetBitRange(tt.from, tt.to); got != tt.set {
+			t.Errorf("0.SetBitRange(%d, %d) = %#x; want %#x", tt.from, tt.to, got, tt.set)
+		}
+		if got := ones.ClearBitRange(tt.from, tt.to); got != tt.set.Not() {
+			t.Errorf("ones.ClearBitRange(%d, %d) = %#x; want %#x", tt.from, tt.to, got, tt.set.Not())
+		}
+		// Equivalent to the BitsSetFrom/BitsClearedFrom composition.
+		want := uint128{}.BitsSetFrom(tt.from).BitsClearedFrom(tt.to + 1)
+		if got := (uint128{}).SetBitRange(tt.from, tt.to); got != want {
+			t.Errorf("0.SetBitRange(%d, %d) = %#x; BitsSetFrom composition gives %#x", tt.from, tt.to, got, want)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("SetBitRange(3, 2) did not panic")
+		}
+	}()
+	ones.SetBitRange(3, 2)
+}
+
+func TestShiftOut(t *testing.T) {
+	mask256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	join := func(hi, lo uint128) *big.Int {
+		b := new(big.Int).Lsh(toBig(hi), 128)
+		return b.Or(b, toBig(lo))
+	}
+	for _, u := range []uint128{
+		{0, 1}, {1 << 63, 0}, {0x0123456789abcdef, 0xfedcba9876543210}, {^uint64(0), ^uint64(0)},
+	} {
+		for _, n := range []uint{0, 1, 4, 63, 64, 65, 127, 128, 129, 200, 255, 256, 300} {
+			r, out := u.LshOut(n)
+			want := new(big.Int).Lsh(toBig(u), n)
+			want.And(want, mask256)
+			if got := join(out, r); got.Cmp(want) != 0 {
+				t.Errorf("%#x.LshOut(%d) = %#x, %#x; want %x", u, n, r, out, want)
+			}
+			r, out = u.RshOut(n)
+			want = new(big.Int).Rsh(join(u, uint128{}), n)
+			if got := join(r, out); got.Cmp(want) != 0 {
+				t.Errorf("%#x.RshOut(%d) = %#x, %#x; want %x", u, n, r, out, want)
+			}
+		}
+	}
+
+	// Shift a 256-bit value (hi, lo) left by 4 using the carried-out bits.
+	hi, lo := uint128{0, 0xf}, uint128{0xf << 60, 1}
+	lo, carry := lo.LshOut(4)
+	hi, _ = hi.LshOut(4)
+	hi = hi.Or(carry)
+	if hi != (uint128{0, 0xff}) || lo != (uint128{0, 0x10}) {
+		t.Errorf("256-bit shift = %#x:%#x; want 0xff:0x10", hi, lo)
+	}
+}
+
+func TestRankSelect(t *testing.T) {
+	for _, u := range []uint128{
+		{0, 0}, {1 << 63, 0}, {0, 1}, {^uint64(0), ^uint64(0)},
+		{0x0123456789abcdef, 0xfedcba9876543210}, {0, 0xf0f0}, {0x8000000000000001, 0},
+	} {
+		rank := 0
+		for i := 0; i < 128; i++ {
+			if got := u.Rank(i); got != rank {
+				t.Errorf("%#x.Rank(%d) = %d; want %d", u, i, got, rank)
+			}
+			if u.Bit(uint8(i)) == 1 {
+				if got := u.Select(rank); got != i {
+					t.Errorf("%#x.Select(%d) = %d; want %d", u, rank, got, i)
+				}
+				rank++
+			}
+		}
+		if got := u.Rank(128); got != u.OnesCount() {
+			t.Errorf("%#x.Rank(128) = %d; want %d", u, got, u.OnesCount())
+		}
+		if got := u.Rank(1000); got != u.OnesCount() {
+			t.Errorf("%#x.Rank(1000) = %d; want %d", u, got, u.OnesCount())
+		}
+		if got := u.Rank(-1); got != 0 {
+			t.Errorf("%#x.Rank(-1) = %d; want 0", u, got)
+		}
+		for _, k := range []int{-1, u.OnesCount(), 200} {
+			if got := u.Select(k); got != -1 {
+				t.Errorf("%#x.Select(%d) = %d; want -1", u, k, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bsonuint128 stores uint128.Uint128 values in MongoDB as BSON
+// Decimal128, so that they compare and aggregate numerically on the
+// server.
+//
+// Decimal128 holds 34 significant decimal digits, so every value below
+// 10^34 (about 2^113) round-trips, while larger values are accepted
+// only if their trailing decimal zeros bring them within 34 digits;
+// marshaling any other value fails rather than rounding. Use the
+// Uint128 type for struct fields:
+//
+//	type Account struct {
+//		Balance bsonuint128.Uint128 `bson:"balance"`
+//	}
+package bsonuint128
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"uint128"
+)
+
+// ToDecimal128 returns the Decimal128 equal to u, or an error if u
+// cannot be represented exactly.
+func ToDecimal128(u uint128.Uint128) (bson.Decimal128, error) {
+	hi, lo, err := u.ToDecimal128()
+	if err != nil {
+		return bson.Decimal128{}, err
+	}
+	return bson.NewDecimal128(hi, lo), nil
+}
+
+// FromDecimal128 returns the integer value of d, or an error if d is
+// not a non-negative integer below 2^128.
+func FromDecimal128(d bson.Decimal128) (uint128.Uint128, error) {
+	hi, lo := d.GetBytes()
+	return uint128.FromDecimal128(hi, lo)
+}
+
+// Uint128 is a uint128.Uint128 that marshals to and from BSON as a
+// Decimal128. Convert with Uint128(u) and uint128.Uint128(v).
+type Uint128 uint128.Uint128
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (u Uint128) MarshalBSONValue() (typ byte, data []byte, err error) {
+	hi, lo, err := uint128.Uint128(u).ToDecimal128()
+	if err != nil {
+		return 0, nil, err
+	}
+	data = make([]byte, 16)
+	binary.LittleEndian.PutUint64(data[:8], lo)
+	binary.LittleEndian.PutUint64(data[8:], hi)
+	return byte(bson.TypeDecimal128), data, nil
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler. Besides
+// Decimal128, it accepts non-negative 32- and 64-bit integers and
+// decimal strings, so that documents written by other tools decode.
+func (u *Uint128) UnmarshalBSONValue(typ byte, data []byte) error {
+	var (
+		v   uint128.Uint128
+		err error
+	)
+	rv := bson.RawValue{Type: bson.Type(typ), Value: data}
+	switch rv.Type {
+	case bson.TypeDecimal128:
+		d, ok := rv.Decimal128OK()
+		if !ok {
+			return errors.New("bsonuint128: invalid Decimal128 value")
+		}
+		v, err = FromDecimal128(d)
+	case bson.TypeInt32, bson.TypeInt64:
+		n, ok := rv.AsInt64OK()
+		if !ok {
+			return errors.New("bsonuint128: invalid integer value")
+		}
+		if v, ok = uint128.TryFrom(n); !ok {
+			err = fmt.Errorf("bsonuint128: cannot decode negative value %d into Uint128", n)
+		}
+	case bson.TypeString:
+		s, ok := rv.StringValueOK()
+		if !ok {
+			return errors.New("bsonuint128: invalid string value")
+		}
+		v, err = uint128.ParseUint128(s, 10)
+	default:
+		return fmt.Errorf("bsonuint128: cannot decode BSON %v into Uint128", rv.Type)
+	}
+	if err != nil {
+		return err
+	}
+	*u = Uint128(v)
+	return nil
+}
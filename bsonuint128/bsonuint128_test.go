@@ -0,0 +1,103 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bsonuint128
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"uint128"
+)
+
+var (
+	_ bson.ValueMarshaler   = Uint128{}
+	_ bson.ValueUnmarshaler = (*Uint128)(nil)
+)
+
+type doc struct {
+	N Uint128 `bson:"n"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"0",
+		"1",
+		"18446744073709551616",
+		"9999999999999999999999999999999999",
+		"100000000000000000000000000000000000000",
+		"340282366920938463463374600000000000000",
+	} {
+		u := uint128.MustParse(s)
+		data, err := bson.Marshal(doc{Uint128(u)})
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", s, err)
+		}
+		// The server sees a Decimal128 with the same value.
+		var raw struct {
+			N bson.Decimal128 `bson:"n"`
+		}
+		if err := bson.Unmarshal(data, &raw); err != nil {
+			t.Fatal(err)
+		}
+		want, err := bson.ParseDecimal128(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := FromDecimal128(raw.N); err != nil || got != u {
+			t.Errorf("stored Decimal128 %v = %v, %v; want %v", raw.N, got, err, u)
+		}
+		if w, err := FromDecimal128(want); err != nil || w != u {
+			t.Errorf("FromDecimal128(ParseDecimal128(%s)) = %v, %v", s, w, err)
+		}
+
+		var out doc
+		if err := bson.Unmarshal(data, &out); err != nil || uint128.Uint128(out.N) != u {
+			t.Errorf("Unmarshal(Marshal(%s)) = %v, %v", s, uint128.Uint128(out.N), err)
+		}
+	}
+
+	if _, err := bson.Marshal(doc{Uint128(uint128.Max)}); err == nil {
+		t.Errorf("Marshal(Max) succeeded; want error for 39 significant digits")
+	}
+}
+
+func TestUnmarshalOtherTypes(t *testing.T) {
+	for _, tt := range []struct {
+		in   any
+		want uint128.Uint128
+	}{
+		{int32(7), uint128.New(0, 7)},
+		{int64(1) << 62, uint128.New(0, 1<<62)},
+		{"340282366920938463463374607431768211455", uint128.Max},
+	} {
+		data, err := bson.Marshal(bson.M{"n": tt.in})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out doc
+		if err := bson.Unmarshal(data, &out); err != nil || uint128.Uint128(out.N) != tt.want {
+			t.Errorf("Unmarshal(%v) = %v, %v; want %v", tt.in, uint128.Uint128(out.N), err, tt.want)
+		}
+	}
+
+	mustDec := func(s string) bson.Decimal128 {
+		d, err := bson.ParseDecimal128(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	for _, in := range []any{int32(-1), int64(-5), "x", 1.5, true, mustDec("1.5"), mustDec("-1"), mustDec("NaN"), mustDec("Infinity"), mustDec("1E+40")} {
+		data, err := bson.Marshal(bson.M{"n": in})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out doc
+		if err := bson.Unmarshal(data, &out); err == nil {
+			t.Errorf("Unmarshal(%v) = %v; want error", in, uint128.Uint128(out.N))
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// A ByteOrder specifies how to convert byte slices into Uint128 values,
+// in the manner of binary.ByteOrder.
+type ByteOrder interface {
+	Uint128([]byte) Uint128
+	PutUint128([]byte, Uint128)
+	String() string
+}
+
+// AppendByteOrder specifies how to append Uint128 values into a byte
+// slice, in the manner of binary.AppendByteOrder.
+type AppendByteOrder interface {
+	AppendUint128([]byte, Uint128) []byte
+	String() string
+}
+
+// BigEndian is the big-endian implementation of ByteOrder and
+// AppendByteOrder. The methods read and write the first 16 bytes of
+// their argument, so a value at offset off inside a larger buffer b is
+// accessed as BigEndian.Uint128(b[off:]).
+var BigEndian bigEndian
+
+// LittleEndian is the little-endian implementation of ByteOrder and
+// AppendByteOrder.
+var LittleEndian littleEndian
+
+type bigEndian struct{}
+
+// Uint128 returns the Uint128 stored big-endian in b[:16]. It panics
+// if len(b) < 16.
+func (bigEndian) Uint128(b []byte) Uint128 {
+	_ = b[15] // bounds check hint to compiler
+	return Uint128{binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:16])}
+}
+
+// PutUint128 stores v big-endian in b[:16]. It panics if len(b) < 16.
+func (bigEndian) PutUint128(b []byte, v Uint128) {
+	_ = b[15] // early bounds check to guarantee safety of writes below
+	binary.BigEndian.PutUint64(b[:8], v.hi)
+	binary.BigEndian.PutUint64(b[8:16], v.lo)
+}
+
+// AppendUint128 appends the 16 bytes of v in big-endian order to b
+// and returns the extended buffer.
+func (bigEndian) AppendUint128(b []byte, v Uint128) []byte {
+	return v.AppendBytes(b)
+}
+
+func (bigEndian) String() string { return "BigEndian" }
+
+func (bigEndian) GoString() string { return "uint128.BigEndian" }
+
+type littleEndian struct{}
+
+// Uint128 returns the Uint128 stored little-endian in b[:16]. It
+// panics if len(b) < 16.
+func (littleEndian) Uint128(b []byte) Uint128 {
+	_ = b[15] // bounds check hint to compiler
+	return Uint128{binary.LittleEndian.Uint64(b[8:16]), binary.LittleEndian.Uint64(b[:8])}
+}
+
+// PutUint128 stores v little-endian in b[:16]. It panics if
+// len(b) < 16.
+func (littleEndian) PutUint128(b []byte, v Uint128) {
+	_ = b[15] // early bounds check to guarantee safety of writes below
+	binary.LittleEndian.PutUint64(b[:8], v.lo)
+	binary.LittleEndian.PutUint64(b[8:16], v.hi)
+}
+
+// AppendUint128 appends the 16 bytes of v in little-endian order to b
+// and returns the extended buffer.
+func (littleEndian) AppendUint128(b []byte, v Uint128) []byte {
+	return v.AppendBytesLE(b)
+}
+
+func (littleEndian) String() string { return "LittleEndian" }
+
+func (littleEndian) GoString() string { return "uint128.LittleEndian" }
+
+// ReadUint128 reads 16 bytes from r and decodes them in the given byte
+// order. If fewer than 16 bytes are available, it returns io.EOF when
+// none were read and io.ErrUnexpectedEOF otherwise, as io.ReadFull.
+func ReadUint128(r io.Reader, order ByteOrder) (Uint128, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return Uint128{}, err
+	}
+	return order.Uint128(b[:]), nil
+}
+
+// WriteUint128 writes the 16-byte encoding of v in the given byte order
+// to w. It returns io.ErrShortWrite if w accepts fewer bytes without
+// reporting an error.
+func WriteUint128(w io.Writer, order ByteOrder, v Uint128) error {
+	var b [16]byte
+	order.PutUint128(b[:], v)
+	n, err := w.Write(b[:])
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestByteOrder(t *testing.T) {
+	u := uint128{0x0102030405060708, 0x090a0b0c0d0e0f10}
+	be := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	le := []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	for _, tt := range []struct {
+		order ByteOrder
+		b     []byte
+		name  string
+	}{
+		{BigEndian, be, "BigEndian"},
+		{LittleEndian, le, "LittleEndian"},
+	} {
+		// Read and write at an offset inside a larger buffer.
+		buf := make([]byte, 3+16+5)
+		copy(buf[3:], tt.b)
+		if got := tt.order.Uint128(buf[3:]); got != u {
+			t.Errorf("%v.Uint128 = %#x; want %#x", tt.order, got, u)
+		}
+		clear(buf)
+		tt.order.PutUint128(buf[3:], u)
+		if !bytes.Equal(buf[3:19], tt.b) || !bytes.Equal(buf[:3], make([]byte, 3)) || !bytes.Equal(buf[19:], make([]byte, 5)) {
+			t.Errorf("%v.PutUint128 wrote %x", tt.order, buf)
+		}
+		prefix := []byte{0xaa, 0xbb}
+		if got := tt.order.(AppendByteOrder).AppendUint128(prefix[:2:2], u); !bytes.Equal(got, append(prefix, tt.b...)) {
+			t.Errorf("%v.AppendUint128 = %x; want %x", tt.order, got, append(prefix, tt.b...))
+		}
+		if got := tt.order.String(); got != tt.name {
+			t.Errorf("String() = %q; want %q", got, tt.name)
+		}
+		if got := fmt.Sprintf("%#v", tt.order); got != "uint128."+tt.name {
+			t.Errorf("GoString() = %q; want %q", got, "uint128."+tt.name)
+		}
+		for _, f := range []func(){
+			func() { tt.order.Uint128(make([]byte, 15)) },
+			func() { tt.order.PutUint128(make([]byte, 15), u) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%v: short buffer did not panic", tt.order)
+					}
+				}()
+				f()
+			}()
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		var b [16]byte
+		BigEndian.PutUint128(b[:], u)
+		if b != u.Bytes() || BigEndian.Uint128(b[:]) != u {
+			t.Fatalf("BigEndian round trip of %v = %x", u, b)
+		}
+		LittleEndian.PutUint128(b[:], u)
+		if b != u.BytesLE() || LittleEndian.Uint128(b[:]) != u {
+			t.Fatalf("LittleEndian round trip of %v = %x", u, b)
+		}
+	}
+}
+
+func TestAppendUint128Allocs(t *testing.T) {
+	u := uint128{0x0102030405060708, 0x090a0b0c0d0e0f10}
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		b := BigEndian.AppendUint128(buf[:0], u)
+		b = LittleEndian.AppendUint128(b, u)
+		_ = b
+	})
+	if allocs != 0 {
+		t.Errorf("AppendUint128 allocates %v times; want 0", allocs)
+	}
+}
+
+// shortWriter accepts at most n bytes per Write without an error.
+type shortWriter struct{ n int }
+
+func (w shortWriter) Write(p []byte) (int, error) { return min(len(p), w.n), nil }
+
+// errWriter fails every Write with err.
+type errWriter struct{ err error }
+
+func (w errWriter) Write(p []byte) (int, error) { return 0, w.err }
+
+func TestReadWriteUint128(t *testing.T) {
+	u := uint128{0x0102030405060708, 0x090a0b0c0d0e0f10}
+	v := uint128{0, 42}
+	var buf bytes.Buffer
+	for _, order := range []ByteOrder{BigEndian, LittleEndian} {
+		buf.Reset()
+		if err := WriteUint128(&buf, order, u); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteUint128(&buf, order, v); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteByte(0xff)
+		var want []byte
+		want = order.(AppendByteOrder).AppendUint128(want, u)
+		want = order.(AppendByteOrder).AppendUint128(want, v)
+		if !bytes.Equal(buf.Bytes(), append(want, 0xff)) {
+			t.Errorf("%v: WriteUint128 wrote %x; want %x", order, buf.Bytes(), want)
+		}
+
+		// A value split across two Reads is still read whole.
+		r := io.MultiReader(bytes.NewReader(buf.Bytes()[:7]), bytes.NewReader(buf.Bytes()[7:]))
+		if got, err := ReadUint128(r, order); err != nil || got != u {
+			t.Errorf("%v: ReadUint128 = %v, %v; want %v", order, got, err, u)
+		}
+		if got, err := ReadUint128(r, order); err != nil || got != v {
+			t.Errorf("%v: ReadUint128 = %v, %v; want %v", order, got, err, v)
+		}
+		if got, err := ReadUint128(r, order); err != io.ErrUnexpectedEOF || got != (uint128{}) {
+			t.Errorf("%v: ReadUint128 on 1 byte = %v, %v; want io.ErrUnexpectedEOF", order, got, err)
+		}
+		if _, err := ReadUint128(r, order); err != io.EOF {
+			t.Errorf("%v: ReadUint128 at end = %v; want io.EOF", order, err)
+		}
+	}
+
+	if err := WriteUint128(shortWriter{15}, BigEndian, u); err != io.ErrShortWrite {
+		t.Errorf("WriteUint128 to a short writer = %v; want io.ErrShortWrite", err)
+	}
+	errWrite := errors.New("write failed")
+	if err := WriteUint128(errWriter{errWrite}, BigEndian, u); err != errWrite {
+		t.Errorf("WriteUint128 to a failing writer = %v; want %v", err, errWrite)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// FromBytesBE returns the Uint128 whose big-endian encoding is b.
+func FromBytesBE(b [16]byte) Uint128 {
+	return Uint128{
+		binary.BigEndian.Uint64(b[:8]),
+		binary.BigEndian.Uint64(b[8:]),
+	}
+}
+
+// Bytes returns the big-endian encoding of u.
+func (u Uint128) Bytes() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], u.hi)
+	binary.BigEndian.PutUint64(b[8:], u.lo)
+	return b
+}
+
+// FromBytesLE returns the Uint128 whose little-endian encoding is b.
+func FromBytesLE(b [16]byte) Uint128 {
+	return Uint128{
+		binary.LittleEndian.Uint64(b[8:]),
+		binary.LittleEndian.Uint64(b[:8]),
+	}
+}
+
+// BytesLE returns the little-endian encoding of u.
+func (u Uint128) BytesLE() [16]byte {
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:8], u.lo)
+	binary.LittleEndian.PutUint64(b[8:], u.hi)
+	return b
+}
+
+// FillBytes sets buf to the big-endian encoding of u, zero-extended
+// to fill all of buf, and returns buf. Like big.Int.FillBytes, it
+// panics if u does not fit in len(buf) bytes.
+func (u Uint128) FillBytes(buf []byte) []byte {
+	clear(buf)
+	for i := len(buf) - 1; i >= 0 && !u.IsZero(); i-- {
+		buf[i] = byte(u.lo)
+		u = u.Rsh(8)
+	}
+	if !u.IsZero() {
+		panic("uint128: buffer too small to fit value")
+	}
+	return buf
+}
+
+// FillBytesLE is like FillBytes but uses the little-endian encoding:
+// u's least significant byte is stored in buf[0].
+func (u Uint128) FillBytesLE(buf []byte) []byte {
+	clear(buf)
+	for i := 0; i < len(buf) && !u.IsZero(); i++ {
+		buf[i] = byte(u.lo)
+		u = u.Rsh(8)
+	}
+	if !u.IsZero() {
+		panic("uint128: buffer too small to fit value")
+	}
+	return buf
+}
+
+// AppendBytes appends the 16-byte big-endian encoding of u to dst and
+// returns the extended buffer.
+func (u Uint128) AppendBytes(dst []byte) []byte {
+	dst = binary.BigEndian.AppendUint64(dst, u.hi)
+	return binary.BigEndian.AppendUint64(dst, u.lo)
+}
+
+// AppendBytesLE appends the 16-byte little-endian encoding of u to dst
+// and returns the extended buffer.
+func (u Uint128) AppendBytesLE(dst []byte) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, u.lo)
+	return binary.LittleEndian.AppendUint64(dst, u.hi)
+}
+
+// SetBytes returns the Uint128 whose big-endian encoding is buf. Like
+// big.Int.SetBytes, buf may be shorter than 16 bytes, in which case it
+// is zero-extended; an empty buf yields 0. It returns an error if buf
+// is longer than 16 bytes.
+func SetBytes(buf []byte) (Uint128, error) {
+	if len(buf) > 16 {
+		return Uint128{}, errors.New("uint128: byte slice longer than 16 bytes")
+	}
+	var b [16]byte
+	copy(b[16-len(buf):], buf)
+	return FromBytesBE(b), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBytesBE(t *testing.T) {
+	u := uint128{0x0001020304050607, 0x08090a0b0c0d0e0f}
+	want := [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	if got := u.Bytes(); got != want {
+		t.Errorf("%#x.Bytes() = %x; want %x", u, got, want)
+	}
+	if got := FromBytesBE(want); got != u {
+		t.Errorf("FromBytesBE(%x) = %#x; want %#x", want, got, u)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		b := u.Bytes()
+		if got := toBig(u).FillBytes(make([]byte, 16)); string(got) != string(b[:]) {
+			t.Fatalf("%v.Bytes() = %x; want %x", u, b, got)
+		}
+		if got := FromBytesBE(b); got != u {
+			t.Fatalf("FromBytesBE(%x) = %v; want %v", b, got, u)
+		}
+	}
+}
+
+func TestBytesLE(t *testing.T) {
+	u := uint128{0x0f0e0d0c0b0a0908, 0x0706050403020100}
+	want := [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	if got := u.BytesLE(); got != want {
+		t.Errorf("%#x.BytesLE() = %x; want %x", u, got, want)
+	}
+	if got := FromBytesLE(want); got != u {
+		t.Errorf("FromBytesLE(%x) = %#x; want %#x", want, got, u)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		be, le := u.Bytes(), u.BytesLE()
+		for j := range be {
+			if be[j] != le[15-j] {
+				t.Fatalf("%v.BytesLE() = %x; not the reverse of Bytes() = %x", u, le, be)
+			}
+		}
+		if got := FromBytesLE(le); got != u {
+			t.Fatalf("FromBytesLE(%x) = %v; want %v", le, got, u)
+		}
+	}
+}
+
+func TestFillBytes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		for _, n := range []int{(u.Len() + 7) / 8, 16, 20} {
+			buf := make([]byte, n)
+			for j := range buf {
+				buf[j] = 0xff // FillBytes must overwrite stale data.
+			}
+			want := toBig(u).FillBytes(make([]byte, n))
+			if got := u.FillBytes(buf); string(got) != string(want) {
+				t.Fatalf("%v.FillBytes(len %d) = %x; want %x", u, n, got, want)
+			}
+			got := u.FillBytesLE(buf)
+			for j := range got {
+				if got[j] != want[n-1-j] {
+					t.Fatalf("%v.FillBytesLE(len %d) = %x; want reverse of %x", u, n, got, want)
+				}
+			}
+		}
+	}
+
+	for _, f := range []func(uint128, []byte) []byte{uint128.FillBytes, uint128.FillBytesLE} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FillBytes of 2^64 into 8 bytes did not panic")
+				}
+			}()
+			f(uint128{1, 0}, make([]byte, 8))
+		}()
+	}
+}
+
+func TestAppendBytes(t *testing.T) {
+	u := uint128{0x0001020304050607, 0x08090a0b0c0d0e0f}
+	prefix := []byte("ab")
+	be, le := u.Bytes(), u.BytesLE()
+	if got, want := u.AppendBytes(prefix), "ab"+string(be[:]); string(got) != want {
+		t.Errorf("AppendBytes = %x; want %x", got, want)
+	}
+	if got, want := u.AppendBytesLE(prefix), "ab"+string(le[:]); string(got) != want {
+		t.Errorf("AppendBytesLE = %x; want %x", got, want)
+	}
+}
+
+func TestSetBytes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		buf := toBig(u).Bytes() // minimal length, empty for 0
+		got, err := SetBytes(buf)
+		if err != nil || got != u {
+			t.Fatalf("SetBytes(%x) = %v, %v; want %v, nil", buf, got, err, u)
+		}
+		b := u.Bytes()
+		if got, err := SetBytes(b[:]); err != nil || got != u {
+			t.Fatalf("SetBytes(%x) = %v, %v; want %v, nil", b, got, err, u)
+		}
+	}
+	if got, err := SetBytes([]byte{1, 2}); err != nil || got != (uint128{0, 0x0102}) {
+		t.Errorf("SetBytes(0102) = %v, %v; want 258, nil", got, err)
+	}
+	if _, err := SetBytes(make([]byte, 17)); err == nil {
+		t.Errorf("SetBytes of 17 bytes: got nil error")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// CBOR (RFC 8949) initial bytes used by the bignum encoding.
+const (
+	cborMajorUint  = 0 << 5
+	cborMajorBytes = 2 << 5
+	cborTagBignum  = 0xc2 // tag 2, positive bignum
+)
+
+// MarshalCBOR implements the cbor.Marshaler interface of
+// github.com/fxamacker/cbor. The encoding is a tag 2 (positive bignum)
+// wrapping the big-endian bytes of u without leading zeros, so zero is
+// an empty byte string.
+func (u Uint128) MarshalCBOR() ([]byte, error) {
+	b := u.Bytes()
+	n := 16 - u.LeadingZeros()/8
+	buf := make([]byte, 0, 3+n)
+	buf = append(buf, cborTagBignum)
+	if n < 24 {
+		buf = append(buf, cborMajorBytes|byte(n))
+	} else {
+		buf = append(buf, cborMajorBytes|24, byte(n))
+	}
+	return append(buf, b[16-n:]...), nil
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface of
+// github.com/fxamacker/cbor. It accepts a single CBOR data item that is
+// either a tag 2 bignum of at most 16 significant bytes, as produced by
+// MarshalCBOR, or an unsigned integer (major type 0).
+func (u *Uint128) UnmarshalCBOR(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("uint128: UnmarshalCBOR: empty input")
+	}
+	var v Uint128
+	if data[0] == cborTagBignum {
+		major, n, rest, ok := cborHead(data[1:])
+		if !ok || major != cborMajorBytes || uint64(len(rest)) != n {
+			return errors.New("uint128: UnmarshalCBOR: invalid bignum")
+		}
+		var err error
+		if v, err = SetBytes(trimLeadingZeros(rest)); err != nil {
+			return errors.New("uint128: UnmarshalCBOR: bignum overflows 128 bits")
+		}
+	} else {
+		major, n, rest, ok := cborHead(data)
+		if !ok || major != cborMajorUint {
+			return errors.New("uint128: UnmarshalCBOR: not an unsigned integer or bignum")
+		}
+		if len(rest) != 0 {
+			return errors.New("uint128: UnmarshalCBOR: trailing data")
+		}
+		v = Uint128{0, n}
+	}
+	*u = v
+	return nil
+}
+
+// cborHead decodes the head of a CBOR data item with a definite
+// argument, returning its major type, its argument and the remaining
+// bytes.
+func cborHead(b []byte) (major byte, arg uint64, rest []byte, ok bool) {
+	if len(b) == 0 {
+		return 0, 0, nil, false
+	}
+	major, info := b[0]&0xe0, b[0]&0x1f
+	b = b[1:]
+	switch {
+	case info < 24:
+		return major, uint64(info), b, true
+	case info > 27:
+		// Reserved values and indefinite lengths.
+		return 0, 0, nil, false
+	}
+	size := 1 << (info - 24)
+	if len(b) < size {
+		return 0, 0, nil, false
+	}
+	var buf [8]byte
+	copy(buf[8-size:], b[:size])
+	return major, binary.BigEndian.Uint64(buf[:]), b[size:], true
+}
+
+// trimLeadingZeros returns b without its leading zero bytes.
+func trimLeadingZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
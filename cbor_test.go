@@ -0,0 +1,87 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func TestMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		want string
+	}{
+		{uint128{}, "c240"},
+		{uint128{0, 1}, "c24101"},
+		{uint128{0, 0x1ff}, "c24201ff"},
+		// RFC 8949, Appendix A.
+		{uint128{1, 0}, "c249010000000000000000"},
+		{Max, "c250ffffffffffffffffffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		b, err := tt.u.MarshalCBOR()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(b); got != tt.want {
+			t.Errorf("%v.MarshalCBOR() = %s; want %s", tt.u, got, tt.want)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r).Rsh(uint(r.Intn(128)))
+		b, _ := u.MarshalCBOR()
+		var v uint128
+		if err := v.UnmarshalCBOR(b); err != nil || v != u {
+			t.Fatalf("UnmarshalCBOR(%x) = %v, %v; want %v", b, v, err, u)
+		}
+	}
+}
+
+func TestUnmarshalCBOR(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint128
+		ok   bool
+	}{
+		{"00", uint128{}, true},
+		{"17", uint128{0, 23}, true},
+		{"1818", uint128{0, 24}, true},
+		{"1903e8", uint128{0, 1000}, true},
+		{"1a000f4240", uint128{0, 1000000}, true},
+		{"1bffffffffffffffff", uint128{0, ^uint64(0)}, true},
+		{"c240", uint128{}, true},
+		{"c249010000000000000000", uint128{1, 0}, true},
+		{"c2580400000102", uint128{0, 0x102}, true},
+		{"c25100ffffffffffffffffffffffffffffffff", Max, true},
+
+		{"", uint128{}, false},
+		{"20", uint128{}, false},                                     // negative integer
+		{"41ff", uint128{}, false},                                   // untagged byte string
+		{"1c", uint128{}, false},                                     // reserved
+		{"19e8", uint128{}, false},                                   // short argument
+		{"0000", uint128{}, false},                                   // trailing data
+		{"c3420100", uint128{}, false},                               // tag 3, negative bignum
+		{"c201", uint128{}, false},                                   // tag 2 on an integer
+		{"c24201", uint128{}, false},                                 // short byte string
+		{"c2410100", uint128{}, false},                               // trailing data
+		{"c25f4101ff", uint128{}, false},                             // indefinite length
+		{"c2510100000000000000000000000000000000", uint128{}, false}, // 2^128
+	}
+	for _, tt := range tests {
+		b, err := hex.DecodeString(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got uint128
+		err = got.UnmarshalCBOR(b)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("UnmarshalCBOR(%s) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
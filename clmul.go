@@ -0,0 +1,93 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "math/bits"
+
+// ClmulFull returns the 256-bit carry-less product of u and m, that is
+// their product as polynomials over GF(2), where bit i (counting from
+// the least significant bit) holds the coefficient of x^i. The upper
+// half is returned in hi and the lower half in lo.
+//
+// The computation takes constant time, independent of the operands.
+func (u Uint128) ClmulFull(m Uint128) (hi, lo Uint128) {
+	h0, l0 := clmul64(u.lo, m.lo)
+	h1, l1 := clmul64(u.hi, m.hi)
+	ha, la := clmul64(u.lo, m.hi)
+	hb, lb := clmul64(u.hi, m.lo)
+	return Uint128{h1, l1 ^ ha ^ hb}, Uint128{h0 ^ la ^ lb, l0}
+}
+
+// clmul64 returns the 128-bit carry-less product of x and y.
+func clmul64(x, y uint64) (hi, lo uint64) {
+	lo = bmul64(x, y)
+	// The high half is the bit-reversed low half of the product of the
+	// bit-reversed operands, shifted by one since a 64×64 product has
+	// only 127 bits.
+	hi = bits.Reverse64(bmul64(bits.Reverse64(x), bits.Reverse64(y))) >> 1
+	return hi, lo
+}
+
+// bmul64 returns the low 64 bits of the carry-less product of x and y
+// in constant time. Integer multiplication is used on operands whose
+// bits are spaced four apart, so that carries fall into the holes and
+// can be masked off; this is the technique of BearSSL's ghash_ctmul64.
+func bmul64(x, y uint64) uint64 {
+	const m0, m1, m2, m3 = 0x1111111111111111, 0x2222222222222222, 0x4444444444444444, 0x8888888888888888
+	x0, x1, x2, x3 := x&m0, x&m1, x&m2, x&m3
+	y0, y1, y2, y3 := y&m0, y&m1, y&m2, y&m3
+	z0 := (x0 * y0) ^ (x1 * y3) ^ (x2 * y2) ^ (x3 * y1)
+	z1 := (x0 * y1) ^ (x1 * y0) ^ (x2 * y3) ^ (x3 * y2)
+	z2 := (x0 * y2) ^ (x1 * y1) ^ (x2 * y0) ^ (x3 * y3)
+	z3 := (x0 * y3) ^ (x1 * y2) ^ (x2 * y1) ^ (x3 * y0)
+	return z0&m0 | z1&m1 | z2&m2 | z3&m3
+}
+
+// MulGHASH returns the product of u and v in GF(2^128) as defined for
+// GHASH in AES-GCM (NIST SP 800-38D), with reduction polynomial
+// x^128 + x^7 + x^2 + x + 1.
+//
+// GHASH numbers bits from the most significant end: a 16-byte block
+// loaded as a big-endian Uint128 holds the coefficient of x^i in bit
+// i, with bit 0 the most significant bit as everywhere in this
+// package. Like ClmulFull, it runs in constant time.
+func (u Uint128) MulGHASH(v Uint128) Uint128 {
+	// Reverse into the usual polynomial order, multiply, and fold the
+	// upper half back with x^128 == x^7 + x^2 + x + 1 twice: the first
+	// fold leaves at most 7 bits above x^127.
+	hi, lo := u.Reverse().ClmulFull(v.Reverse())
+	fold := func(h Uint128) (hi, lo Uint128) {
+		lo = h.Xor(h.Lsh(1)).Xor(h.Lsh(2)).Xor(h.Lsh(7))
+		hi = h.Rsh(127).Xor(h.Rsh(126)).Xor(h.Rsh(121))
+		return hi, lo
+	}
+	over, t := fold(hi)
+	_, t2 := fold(over)
+	return lo.Xor(t).Xor(t2).Reverse()
+}
+
+// polyvalQ is the POLYVAL reduction polynomial
+// x^128 + x^127 + x^126 + x^121 + 1 without its x^128 term.
+// Conveniently, it is its own inverse modulo x^128.
+var polyvalQ = Uint128{0xc200000000000000, 1}
+
+// MulPOLYVAL returns the POLYVAL field product of u and v, that is
+// u*v*x^-128 in GF(2^128) with reduction polynomial
+// x^128 + x^127 + x^126 + x^121 + 1, as defined for AES-GCM-SIV in
+// RFC 8452.
+//
+// POLYVAL numbers bits from the least significant end: a 16-byte block
+// loaded as a little-endian Uint128 holds the coefficient of x^i in
+// bit i counting from the least significant bit. Like ClmulFull, it
+// runs in constant time.
+func (u Uint128) MulPOLYVAL(v Uint128) Uint128 {
+	// Montgomery reduction with R = x^128: adding m*q, where
+	// m = lo * q^-1 mod x^128, clears the low half of the product,
+	// leaving (hi, lo)/x^128 in the high half.
+	hi, lo := u.ClmulFull(v)
+	_, m := lo.ClmulFull(polyvalQ)
+	mqHi, _ := m.ClmulFull(polyvalQ)
+	return hi.Xor(m).Xor(mqHi)
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+// clmulSlow is the schoolbook shift-and-XOR carry-less product.
+func clmulSlow(a, b uint128) (hi, lo uint128) {
+	for i := uint(0); i < 128; i++ {
+		if b.Rsh(i).lo&1 != 0 {
+			lo = lo.Xor(a.Lsh(i))
+			if i > 0 {
+				hi = hi.Xor(a.Rsh(128 - i))
+			}
+		}
+	}
+	return hi, lo
+}
+
+func TestClmulFull(t *testing.T) {
+	ones := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b, hi, lo uint128
+	}{
+		{uint128{0, 0}, ones, uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 1}, ones, uint128{0, 0}, ones},
+		{uint128{0, 3}, uint128{0, 3}, uint128{0, 0}, uint128{0, 5}}, // (x+1)² = x²+1
+		{uint128{0, 7}, uint128{0, 3}, uint128{0, 0}, uint128{0, 9}}, // (x²+x+1)(x+1) = x³+1
+		{uint128{1 << 63, 0}, uint128{1 << 63, 0}, uint128{1 << 62, 0}, uint128{0, 0}},
+		{ones, ones, uint128{0x5555555555555555, 0x5555555555555555}, uint128{0x5555555555555555, 0x5555555555555555}},
+	}
+	for _, tt := range tests {
+		hi, lo := tt.a.ClmulFull(tt.b)
+		if hi != tt.hi || lo != tt.lo {
+			t.Errorf("%#x.ClmulFull(%#x) = %#x, %#x; want %#x, %#x", tt.a, tt.b, hi, lo, tt.hi, tt.lo)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, b := randUint128(rnd), randUint128(rnd)
+		hi, lo := a.ClmulFull(b)
+		wantHi, wantLo := clmulSlow(a, b)
+		if hi != wantHi || lo != wantLo {
+			t.Errorf("%#x.ClmulFull(%#x) = %#x, %#x; want %#x, %#x", a, b, hi, lo, wantHi, wantLo)
+		}
+	}
+}
+
+// ghashSlow is the bit-serial multiplication of NIST SP 800-38D,
+// Algorithm 1.
+func ghashSlow(x, y uint128) uint128 {
+	r := uint128{0xe1 << 56, 0}
+	var z uint128
+	v := y
+	for i := uint8(0); i < 128; i++ {
+		if x.Bit(i) == 1 {
+			z = z.Xor(v)
+		}
+		if v.lo&1 == 0 {
+			v = v.Rsh(1)
+		} else {
+			v = v.Rsh(1).Xor(r)
+		}
+	}
+	return z
+}
+
+func TestMulGHASH(t *testing.T) {
+	one := uint128{1 << 63, 0} // x^0 in GHASH bit order
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, b := randUint128(rnd), randUint128(rnd)
+		if got, want := a.MulGHASH(b), ghashSlow(a, b); got != want {
+			t.Errorf("%#x.MulGHASH(%#x) = %#x; want %#x", a, b, got, want)
+		}
+		if got := a.MulGHASH(one); got != a {
+			t.Errorf("%#x.MulGHASH(1) = %#x", a, got)
+		}
+	}
+}
+
+func TestGHASHMatchesGCM(t *testing.T) {
+	// Recompute an AES-GCM tag over additional data only and compare it
+	// with crypto/cipher: tag = E(K, J0) ^ GHASH_H(A || len(A) || len(C)).
+	key := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := []byte("unique nonce")
+	aad := []byte("additional data spanning more than one GHASH block")
+	tag := gcm.Seal(nil, nonce, nil, aad)
+
+	load := func(b []byte) uint128 {
+		return uint128{binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])}
+	}
+	var hb [16]byte
+	block.Encrypt(hb[:], hb[:])
+	h := load(hb[:])
+	var y uint128
+	for i := 0; i < len(aad); i += 16 {
+		var blk [16]byte
+		copy(blk[:], aad[i:])
+		y = y.Xor(load(blk[:])).MulGHASH(h)
+	}
+	y = y.Xor(uint128{uint64(len(aad)) * 8, 0}).MulGHASH(h)
+	var j0 [16]byte
+	copy(j0[:], nonce)
+	j0[15] = 1
+	block.Encrypt(j0[:], j0[:])
+	if got, want := y.Xor(load(j0[:])), load(tag); got != want {
+		t.Errorf("GHASH-based tag = %#x; crypto/cipher gives %#x", got, want)
+	}
+}
+
+func TestMulPOLYVAL(t *testing.T) {
+	// The example from RFC 8452, Appendix A.
+	load := func(s string) uint128 {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uint128{binary.LittleEndian.Uint64(b[8:]), binary.LittleEndian.Uint64(b)}
+	}
+	h := load("25629347589242761d31f826ba4b757b")
+	x1 := load("4f4f95668c83dfb6401762bb2d01a262")
+	x2 := load("d1a24ddd2721d006bbe45f20d3c9f362")
+	want := load("f7a3b47b846119fae5b7866cf5e5b77e")
+	s := x1.MulPOLYVAL(h)
+	s = s.Xor(x2).MulPOLYVAL(h)
+	if s != want {
+		t.Errorf("POLYVAL(H, X1, X2) = %#x; want %#x", s, want)
+	}
+
+	// x^128 is the multiplicative identity of the Montgomery product,
+	// represented by x^128 mod q == polyvalQ.
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		a := randUint128(rnd)
+		if got := a.MulPOLYVAL(polyvalQ); got != a {
+			t.Errorf("%#x.MulPOLYVAL(x^128) = %#x", a, got)
+		}
+		b := randUint128(rnd)
+		if a.MulPOLYVAL(b) != b.MulPOLYVAL(a) {
+			t.Errorf("MulPOLYVAL not commutative for %#x, %#x", a, b)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "errors"
+
+// IEEE 754-2008 decimal128 in the binary integer decimal (BID)
+// encoding, as used by BSON: a sign bit, a 14-bit exponent biased by
+// 6176 and a coefficient of up to 34 decimal digits.
+const (
+	dec128Bias    = 6176
+	dec128ExpMask = 1<<14 - 1
+)
+
+// dec128MaxCoeff is 10^34 - 1, the largest decimal128 coefficient.
+var dec128MaxCoeff = pow10tab[34].SubOne()
+
+// ToDecimal128 returns the bits of the IEEE 754 decimal128 value (in
+// the BID encoding used by BSON Decimal128) that represents u exactly,
+// as the high and low 64-bit words. Every value below 10^34, about
+// 2^113, is representable, as are larger values with enough trailing
+// decimal zeros that the rest fits in 34 digits; for other values it
+// returns an error rather than rounding.
+func (u Uint128) ToDecimal128() (hi, lo uint64, err error) {
+	exp := 0
+	for u.Cmp(dec128MaxCoeff) > 0 {
+		q, r := u.Div64(10)
+		if r != 0 {
+			return 0, 0, errors.New("uint128: value has more than 34 significant digits and cannot be represented exactly as a decimal128")
+		}
+		u, exp = q, exp+1
+	}
+	return uint64(exp+dec128Bias)<<49 | u.hi, u.lo, nil
+}
+
+// FromDecimal128 returns the integer value of the IEEE 754 decimal128
+// value with the given high and low 64-bit words (in the BID encoding
+// used by BSON Decimal128). It returns an error for NaNs, infinities,
+// negative values and values with a fractional part or of 2^128 or
+// more. Negative zero converts to 0, and non-canonical coefficients
+// are taken as zero, as the standard prescribes.
+func FromDecimal128(hi, lo uint64) (Uint128, error) {
+	var (
+		coeff Uint128
+		exp   int
+	)
+	if hi>>61&3 == 3 {
+		switch hi >> 58 & 0x1f {
+		case 0x1e:
+			return Uint128{}, errors.New("uint128: decimal128 infinity")
+		case 0x1f:
+			return Uint128{}, errors.New("uint128: decimal128 NaN")
+		}
+		// The coefficient would be at least 2^113 > 10^34 - 1: it is
+		// non-canonical, and hence zero.
+		exp = int(hi>>47&dec128ExpMask) - dec128Bias
+	} else {
+		coeff = Uint128{hi & (1<<49 - 1), lo}
+		if coeff.Cmp(dec128MaxCoeff) > 0 {
+			coeff = Uint128{}
+		}
+		exp = int(hi>>49&dec128ExpMask) - dec128Bias
+	}
+	switch {
+	case coeff.IsZero():
+		return Uint128{}, nil
+	case hi>>63 != 0:
+		return Uint128{}, errors.New("uint128: negative decimal128 value")
+	case exp >= 0:
+		if exp < len(pow10tab) {
+			if v, ok := coeff.MulChecked(pow10tab[exp]); ok {
+				return v, nil
+			}
+		}
+		return Uint128{}, errors.New("uint128: decimal128 value out of range")
+	default:
+		if -exp < len(pow10tab) {
+			if q, r := coeff.QuoRem(pow10tab[-exp]); r.IsZero() {
+				return q, nil
+			}
+		}
+		return Uint128{}, errors.New("uint128: decimal128 value is not an integer")
+	}
+}
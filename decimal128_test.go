@@ -0,0 +1,87 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDecimal128(t *testing.T) {
+	// Vectors from the BSON corpus (decimal128-1.json).
+	tests := []struct {
+		u      uint128
+		hi, lo uint64
+	}{
+		{uint128{}, 0x3040000000000000, 0},
+		{uint128{0, 1}, 0x3040000000000000, 1},
+		{uint128{0, 12345}, 0x3040000000000000, 12345},
+		{pow10tab[34].SubOne(), 0x3041ed09bead87c0, 0x378d8e63ffffffff}, // 9999999999999999999999999999999999
+		{pow10tab[34], 0x3042314dc6448d93, 0x38c15b0a00000000},
+		{pow10tab[38], 0x304a314dc6448d93, 0x38c15b0a00000000},
+	}
+	for _, tt := range tests {
+		hi, lo, err := tt.u.ToDecimal128()
+		if err != nil || hi != tt.hi || lo != tt.lo {
+			t.Errorf("%v.ToDecimal128() = %#x, %#x, %v; want %#x, %#x", tt.u, hi, lo, err, tt.hi, tt.lo)
+		}
+		if got, err := FromDecimal128(tt.hi, tt.lo); err != nil || got != tt.u {
+			t.Errorf("FromDecimal128(%#x, %#x) = %v, %v; want %v", tt.hi, tt.lo, got, err, tt.u)
+		}
+	}
+
+	for _, u := range []uint128{pow10tab[34].AddOne(), Max, pow10tab[38].AddOne()} {
+		if hi, lo, err := u.ToDecimal128(); err == nil {
+			t.Errorf("%v.ToDecimal128() = %#x, %#x; want error", u, hi, lo)
+		}
+	}
+
+	for _, tt := range []struct {
+		hi, lo uint64
+		want   uint128
+	}{
+		{0x303e000000000000, 10, uint128{0, 1}},     // 1.0
+		{0xb040000000000000, 0, uint128{}},          // -0
+		{0x5ffe000000000000, 0, uint128{}},          // 0E+6111
+		{0x6c10000000000000, 0, uint128{}},          // non-canonical coefficient
+		{0x3041ffffffffffff, ^uint64(0), uint128{}}, // coefficient 2^113-1 > 10^34-1
+		{0x3046000000000000, 1, uint128{0, 1000}},   // 1E+3
+		{0x3032000000000000, 1e10, uint128{0, 1e3}}, // 1000.0000000
+	} {
+		if got, err := FromDecimal128(tt.hi, tt.lo); err != nil || got != tt.want {
+			t.Errorf("FromDecimal128(%#x, %#x) = %v, %v; want %v", tt.hi, tt.lo, got, err, tt.want)
+		}
+	}
+	for _, tt := range []struct{ hi, lo uint64 }{
+		{0x7800000000000000, 0},                          // Infinity
+		{0xf800000000000000, 0},                          // -Infinity
+		{0x7c00000000000000, 0},                          // NaN
+		{0x7e00000000000000, 0},                          // sNaN
+		{0xb040000000000000, 1},                          // -1
+		{0x303e000000000000, 1},                          // 0.1
+		{0x3041ed09bead87c0 + 5<<49, 0x378d8e63ffffffff}, // (10^34-1)E+5 > 2^128
+		{0x5ffe000000000000, 1},                          // 1E+6111
+		{0x0000000000000000, 1},                          // 1E-6176
+	} {
+		if got, err := FromDecimal128(tt.hi, tt.lo); err == nil {
+			t.Errorf("FromDecimal128(%#x, %#x) = %v; want error", tt.hi, tt.lo, got)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		if u.Cmp(pow10tab[34]) >= 0 {
+			u = u.Mod(pow10tab[34]).Mul(pow10tab[r.Intn(5)])
+		}
+		hi, lo, err := u.ToDecimal128()
+		if err != nil {
+			t.Fatalf("%v.ToDecimal128(): %v", u, err)
+		}
+		if got, err := FromDecimal128(hi, lo); err != nil || got != u {
+			t.Fatalf("FromDecimal128(%v.ToDecimal128()) = %v, %v", u, got, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "math/bits"
+
+// Div64 returns the quotient and remainder of u divided by d.
+// Like the built-in integer division, it panics if d is zero.
+//
+// It is much cheaper than a full 128-bit division when the divisor
+// fits in 64 bits.
+func (u Uint128) Div64(d uint64) (q Uint128, rem uint64) {
+	q.hi, rem = u.hi/d, u.hi%d
+	q.lo, rem = bits.Div64(rem, u.lo, d)
+	return
+}
+
+// QuoRem returns the quotient u/v and remainder u%v.
+//
+// Division by zero is not defined for unsigned integers any more than
+// it is for the built-in types, so QuoRem, Div and Mod all panic with
+// a run-time divide-by-zero error if v is zero, exactly like the Go
+// / and % operators.
+func (u Uint128) QuoRem(v Uint128) (q, r Uint128) {
+	if v.hi == 0 {
+		var r64 uint64
+		q, r64 = u.Div64(v.lo)
+		return q, Uint128{0, r64}
+	}
+
+	// This is Knuth's Algorithm D specialized to a two-limb divisor,
+	// in the form given in Hacker's Delight, 2nd ed., §9-5: normalize v
+	// so that its top bit is set, estimate the single-limb quotient from
+	// the top 128 bits of u/2 using the 128/64 hardware division, and
+	// correct the (at most one too small) estimate.
+	n := uint(bits.LeadingZeros64(v.hi))
+	v1 := v.hi<<n | v.lo>>(64-n)
+	u1hi, u1lo := u.hi>>1, u.lo>>1|u.hi<<63
+	tq, _ := bits.Div64(u1hi, u1lo, v1)
+	tq >>= 63 - n
+	if tq != 0 {
+		tq--
+	}
+	q = Uint128{0, tq}
+	r = u.Sub(v.Mul64(tq))
+	if r.Cmp(v) >= 0 {
+		q = q.AddOne()
+		r = r.Sub(v)
+	}
+	return q, r
+}
+
+// Div returns the quotient u/v. It panics if v is zero; see QuoRem.
+func (u Uint128) Div(v Uint128) Uint128 {
+	q, _ := u.QuoRem(v)
+	return q
+}
+
+// Mod returns the remainder u%v. It panics if v is zero; see QuoRem.
+func (u Uint128) Mod(v Uint128) Uint128 {
+	_, r := u.QuoRem(v)
+	return r
+}
+
+// MulDiv returns floor(a*b/c), computed from the full 256-bit product
+// so that the intermediate a*b cannot overflow. The result is exact
+// when ok is true; if the quotient does not fit in 128 bits, its low
+// 128 bits are returned with ok == false.
+//
+// MulDiv panics if c is zero.
+func MulDiv(a, b, c Uint128) (q Uint128, ok bool) {
+	q, _, ok = MulDivRem(a, b, c)
+	return q, ok
+}
+
+// MulDivRem is like MulDiv but also returns the remainder (a*b) mod c,
+// which is exact even when the quotient overflows.
+func MulDivRem(a, b, c Uint128) (q, r Uint128, ok bool) {
+	hi, lo := a.MulFull(b)
+	d := NewDivisor(c)
+	ok = true
+	if hi.Cmp(c) >= 0 {
+		// The quotient has more than 128 bits. Dropping the multiple of
+		// c*2^128 from the product keeps the low quotient bits and the
+		// remainder intact.
+		hi = d.Mod(hi)
+		ok = false
+	}
+	q, r = d.quoRem256(hi, lo)
+	return q, r, ok
+}
+
+// A RoundingMode selects how DivRound rounds an inexact quotient.
+type RoundingMode byte
+
+// These constants define the supported rounding modes.
+const (
+	RoundFloor    RoundingMode = iota // round down, like Div
+	RoundCeil                         // round up
+	RoundHalfUp                       // round to nearest, ties up
+	RoundHalfEven                     // round to nearest, ties to even (banker's rounding)
+)
+
+// DivRound returns u/v rounded according to mode.
+// It panics if v is zero or mode is not one of the RoundingMode
+// constants.
+func (u Uint128) DivRound(v Uint128, mode RoundingMode) Uint128 {
+	q, r := u.QuoRem(v)
+	if r.IsZero() {
+		if mode > RoundHalfEven {
+			panic("uint128: invalid rounding mode")
+		}
+		return q
+	}
+	// r != 0 implies v >= 2 and thus q < 2^127, so q+1 cannot overflow.
+	// Compare r against v-r rather than 2r against v to avoid overflow.
+	var up bool
+	switch mode {
+	case RoundFloor:
+	case RoundCeil:
+		up = true
+	case RoundHalfUp:
+		up = r.Cmp(v.Sub(r)) >= 0
+	case RoundHalfEven:
+		c := r.Cmp(v.Sub(r))
+		up = c > 0 || (c == 0 && q.lo&1 != 0)
+	default:
+		panic("uint128: invalid rounding mode")
+	}
+	if up {
+		q = q.AddOne()
+	}
+	return q
+}
+
+// CeilDiv returns u/v rounded up. It panics if v is zero.
+func (u Uint128) CeilDiv(v Uint128) Uint128 {
+	return u.DivRound(v, RoundCeil)
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestDiv64(t *testing.T) {
+	tests := []struct {
+		u uint128
+		d uint64
+	}{
+		{uint128{0, 0}, 1},
+		{uint128{0, 100}, 7},
+		{uint128{1, 0}, 2},
+		{uint128{1, 0}, 3},
+		{uint128{5, 12345}, 5},
+		{uint128{^uint64(0), ^uint64(0)}, 1},
+		{uint128{^uint64(0), ^uint64(0)}, 10},
+		{uint128{^uint64(0), ^uint64(0)}, ^uint64(0)},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, 0x00000000ffffffff},
+		{uint128{0, 6}, 7},
+	}
+	for _, tt := range tests {
+		q, r := tt.u.Div64(tt.d)
+		wantQ, wantR := new(big.Int).QuoRem(toBig(tt.u), new(big.Int).SetUint64(tt.d), new(big.Int))
+		if toBig(q).Cmp(wantQ) != 0 || r != wantR.Uint64() {
+			t.Errorf("%v.Div64(%d) = %v, %d; want %v, %d", tt.u, tt.d, q, r, fromBig(wantQ), wantR.Uint64())
+		}
+	}
+}
+
+func TestDiv64ByZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Div64(0) did not panic")
+		}
+	}()
+	uint128{0, 1}.Div64(0)
+}
+
+// divTests are dividend/divisor pairs shared by the division tests.
+var divTests = []struct {
+	u, v uint128
+}{
+	{uint128{0, 0}, uint128{0, 1}},
+	{uint128{0, 100}, uint128{0, 7}},
+	{uint128{1, 0}, uint128{0, 3}},
+	{uint128{1, 0}, uint128{1, 0}},
+	{uint128{1, 0}, uint128{1, 1}},
+	{uint128{2, 5}, uint128{1, 0}},
+	{uint128{0, 5}, uint128{1, 0}},
+	{uint128{^uint64(0), ^uint64(0)}, uint128{0, 10}},
+	{uint128{^uint64(0), ^uint64(0)}, uint128{^uint64(0), ^uint64(0)}},
+	{uint128{^uint64(0), ^uint64(0)}, uint128{^uint64(0), ^uint64(0) - 1}},
+	{uint128{^uint64(0), ^uint64(0)}, uint128{1 << 63, 0}},
+	{uint128{^uint64(0), 0}, uint128{1, ^uint64(0)}},
+	{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x1, 0x8796a5b4c3d2e1f0}},
+	{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x0123456789abcdee, 0xffffffffffffffff}},
+	{uint128{0x8000000000000000, 0}, uint128{0x7fffffffffffffff, 0xffffffffffffffff}},
+	{uint128{0xfffffffffffffffe, 1}, uint128{0xffffffffffffffff, 0}},
+}
+
+func TestQuoRem(t *testing.T) {
+	for _, tt := range divTests {
+		q, r := tt.u.QuoRem(tt.v)
+		wantQ, wantR := new(big.Int).QuoRem(toBig(tt.u), toBig(tt.v), new(big.Int))
+		if toBig(q).Cmp(wantQ) != 0 || toBig(r).Cmp(wantR) != 0 {
+			t.Errorf("%v.QuoRem(%v) = %v, %v; want %v, %v", tt.u, tt.v, q, r, fromBig(wantQ), fromBig(wantR))
+		}
+		if got := tt.u.Div(tt.v); got != q {
+			t.Errorf("%v.Div(%v) = %v; want %v", tt.u, tt.v, got, q)
+		}
+		if got := tt.u.Mod(tt.v); got != r {
+			t.Errorf("%v.Mod(%v) = %v; want %v", tt.u, tt.v, got, r)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		u, v := randUint128(rnd), randUint128(rnd)
+		if v.IsZero() {
+			continue
+		}
+		q, r := u.QuoRem(v)
+		wantQ, wantR := new(big.Int).QuoRem(toBig(u), toBig(v), new(big.Int))
+		if toBig(q).Cmp(wantQ) != 0 || toBig(r).Cmp(wantR) != 0 {
+			t.Fatalf("%v.QuoRem(%v) = %v, %v; want %v, %v", u, v, q, r, fromBig(wantQ), fromBig(wantR))
+		}
+	}
+}
+
+func TestQuoRemByZero(t *testing.T) {
+	for _, f := range []func(){
+		func() { uint128{1, 1}.QuoRem(uint128{}) },
+		func() { uint128{1, 1}.Div(uint128{}) },
+		func() { uint128{1, 1}.Mod(uint128{}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("division by zero did not panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestMulDiv(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b, c uint128
+	}{
+		{uint128{0, 6}, uint128{0, 7}, uint128{0, 4}},
+		{max, max, max},
+		{max, max, uint128{^uint64(0), ^uint64(0) - 1}},
+		{max, uint128{0, 3}, uint128{0, 7}},
+		{max, uint128{0, 2}, uint128{0, 1}},
+		{max, max, uint128{0, 3}},
+		{uint128{1 << 63, 0}, uint128{0, 1 << 1}, uint128{1, 0}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x0f1e2d3c4b5a6978, 0x8796a5b4c3d2e1f0}, uint128{0x1234, 0x5678}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x0f1e2d3c4b5a6978, 0x8796a5b4c3d2e1f0}, uint128{0, 0x5678}},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		tt := struct{ a, b, c uint128 }{randUint128(rnd), randUint128(rnd), randUint128(rnd)}
+		if !tt.c.IsZero() {
+			tests = append(tests, tt)
+		}
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, tt := range tests {
+		prod := new(big.Int).Mul(toBig(tt.a), toBig(tt.b))
+		wantQ, wantR := new(big.Int).QuoRem(prod, toBig(tt.c), new(big.Int))
+		wantOK := wantQ.Cmp(limit) < 0
+		q, r, ok := MulDivRem(tt.a, tt.b, tt.c)
+		if q != fromBig(wantQ) || toBig(r).Cmp(wantR) != 0 || ok != wantOK {
+			t.Errorf("MulDivRem(%v, %v, %v) = %v, %v, %v; want %v, %v, %v", tt.a, tt.b, tt.c, q, r, ok, fromBig(wantQ), fromBig(wantR), wantOK)
+		}
+		if q2, ok2 := MulDiv(tt.a, tt.b, tt.c); q2 != q || ok2 != ok {
+			t.Errorf("MulDiv(%v, %v, %v) = %v, %v; want %v, %v", tt.a, tt.b, tt.c, q2, ok2, q, ok)
+		}
+	}
+}
+
+func TestMulDivByZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MulDiv with zero divisor did not panic")
+		}
+	}()
+	MulDiv(uint128{0, 1}, uint128{0, 1}, uint128{})
+}
+
+func TestDivRound(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		u, v                      uint128
+		floor, ceil, up, halfEven uint128
+	}{
+		{uint128{0, 10}, uint128{0, 5}, uint128{0, 2}, uint128{0, 2}, uint128{0, 2}, uint128{0, 2}},
+		{uint128{0, 11}, uint128{0, 5}, uint128{0, 2}, uint128{0, 3}, uint128{0, 2}, uint128{0, 2}},
+		{uint128{0, 13}, uint128{0, 5}, uint128{0, 2}, uint128{0, 3}, uint128{0, 3}, uint128{0, 3}},
+		{uint128{0, 5}, uint128{0, 2}, uint128{0, 2}, uint128{0, 3}, uint128{0, 3}, uint128{0, 2}},
+		{uint128{0, 7}, uint128{0, 2}, uint128{0, 3}, uint128{0, 4}, uint128{0, 4}, uint128{0, 4}},
+		{uint128{0, 1}, uint128{0, 2}, uint128{0, 0}, uint128{0, 1}, uint128{0, 1}, uint128{0, 0}},
+		{uint128{0, 0}, uint128{0, 7}, uint128{0, 0}, uint128{0, 0}, uint128{0, 0}, uint128{0, 0}},
+		{max, uint128{0, 1}, max, max, max, max},
+		{max, uint128{0, 2}, uint128{1<<63 - 1, ^uint64(0)}, uint128{1 << 63, 0}, uint128{1 << 63, 0}, uint128{1 << 63, 0}},
+		{max, max.SubOne(), uint128{0, 1}, uint128{0, 2}, uint128{0, 1}, uint128{0, 1}},
+		{max.SubOne(), max, uint128{0, 0}, uint128{0, 1}, uint128{0, 1}, uint128{0, 1}},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct {
+			mode RoundingMode
+			want uint128
+		}{{RoundFloor, tt.floor}, {RoundCeil, tt.ceil}, {RoundHalfUp, tt.up}, {RoundHalfEven, tt.halfEven}} {
+			if got := tt.u.DivRound(tt.v, c.mode); got != c.want {
+				t.Errorf("%v.DivRound(%v, %d) = %v; want %v", tt.u, tt.v, c.mode, got, c.want)
+			}
+		}
+		if got := tt.u.CeilDiv(tt.v); got != tt.ceil {
+			t.Errorf("%v.CeilDiv(%v) = %v; want %v", tt.u, tt.v, got, tt.ceil)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("DivRound with invalid mode did not panic")
+		}
+	}()
+	uint128{0, 4}.DivRound(uint128{0, 2}, RoundHalfEven+1)
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "math/bits"
+
+// A Divisor is a fixed divisor with a precomputed reciprocal, for
+// callers that divide many values by the same modulus. Its methods
+// replace the hardware division in QuoRem by a few multiplications,
+// following Möller and Granlund, "Improved division by invariant
+// integers" (IEEE Trans. Computers, 2011).
+//
+// The zero Divisor is not usable; create one with NewDivisor.
+type Divisor struct {
+	d     Uint128 // the divisor
+	shift uint    // normalization shift: leading zeros of d
+	n1    uint64  // high limb of d<<shift (the only limb for 64-bit divisors)
+	n0    uint64  // low limb of d<<shift
+	v     uint64  // reciprocal of the normalized divisor
+}
+
+// NewDivisor returns a Divisor for d.
+// Like division by zero, it panics if d is zero.
+func NewDivisor(d Uint128) Divisor {
+	if d.hi == 0 {
+		s := uint(bits.LeadingZeros64(d.lo))
+		n := d.lo << s
+		return Divisor{d: d, shift: s, n1: n, v: reciprocal(n)}
+	}
+	s := uint(bits.LeadingZeros64(d.hi))
+	n1, n0 := d.hi<<s|d.lo>>(64-s), d.lo<<s
+	return Divisor{d: d, shift: s, n1: n1, n0: n0, v: reciprocal3by2(n1, n0)}
+}
+
+// Value returns the divisor d was created with.
+func (d Divisor) Value() Uint128 { return d.d }
+
+// QuoRem returns the quotient u/d.Value() and remainder u%d.Value().
+func (d Divisor) QuoRem(u Uint128) (q, r Uint128) {
+	s := d.shift
+	// u<<s as three limbs; u2 is always below the normalized divisor.
+	// Go defines shifts by 64 as producing 0, which covers s == 0.
+	u2, u1, u0 := u.hi>>(64-s), u.hi<<s|u.lo>>(64-s), u.lo<<s
+	if d.d.hi == 0 {
+		var rem uint64
+		q.hi, rem = div2by1(u2, u1, d.n1, d.v)
+		q.lo, rem = div2by1(rem, u0, d.n1, d.v)
+		return q, Uint128{0, rem >> s}
+	}
+	q.lo, r = div3by2(u2, u1, u0, d.n1, d.n0, d.v)
+	return q, Uint128{r.hi >> s, r.lo>>s | r.hi<<(64-s)}
+}
+
+// Div returns u/d.Value().
+func (d Divisor) Div(u Uint128) Uint128 {
+	q, _ := d.QuoRem(u)
+	return q
+}
+
+// Mod returns u%d.Value().
+func (d Divisor) Mod(u Uint128) Uint128 {
+	_, r := d.QuoRem(u)
+	return r
+}
+
+// reciprocal returns floor((2^128-1)/d) - 2^64 for a normalized d
+// (one with its top bit set). It panics if d is zero.
+func reciprocal(d uint64) uint64 {
+	v, _ := bits.Div64(^d, ^uint64(0), d)
+	return v
+}
+
+// reciprocal3by2 returns floor((2^192-1)/(d1, d0)) - 2^64 for a
+// normalized two-limb divisor (Algorithm 6 of Möller and Granlund).
+func reciprocal3by2(d1, d0 uint64) uint64 {
+	v := reciprocal(d1)
+	p := d1 * v
+	p += d0
+	if p < d0 {
+		v--
+		if p >= d1 {
+			v--
+			p -= d1
+		}
+		p -= d1
+	}
+	t1, t0 := bits.Mul64(v, d0)
+	p += t1
+	if p < t1 {
+		v--
+		if p > d1 || (p == d1 && t0 >= d0) {
+			v--
+		}
+	}
+	return v
+}
+
+// div2by1 divides (u1, u0) by the normalized d using its reciprocal v
+// (Algorithm 4 of Möller and Granlund). It requires u1 < d.
+func div2by1(u1, u0, d, v uint64) (q, r uint64) {
+	q1, q0 := bits.Mul64(v, u1)
+	var c uint64
+	q0, c = bits.Add64(q0, u0, 0)
+	q1, _ = bits.Add64(q1, u1, c)
+	q1++
+	r = u0 - q1*d
+	if r > q0 {
+		q1--
+		r += d
+	}
+	if r >= d {
+		q1++
+		r -= d
+	}
+	return q1, r
+}
+
+// div3by2 divides (u2, u1, u0) by the normalized (d1, d0) using its
+// reciprocal v (Algorithm 5 of Möller and Granlund).
+// It requires (u2, u1) < (d1, d0).
+func div3by2(u2, u1, u0, d1, d0, v uint64) (q uint64, r Uint128) {
+	q1, q0 := bits.Mul64(v, u2)
+	var c uint64
+	q0, c = bits.Add64(q0, u1, 0)
+	q1, _ = bits.Add64(q1, u2, c)
+	r1 := u1 - q1*d1
+	t1, t0 := bits.Mul64(d0, q1)
+	d := Uint128{d1, d0}
+	r = Uint128{r1, u0}.Sub(Uint128{t1, t0}).Sub(d)
+	q1++
+	if r.hi >= q0 {
+		q1--
+		r = r.Add(d)
+	}
+	if r.hi > d1 || (r.hi == d1 && r.lo >= d0) {
+		q1++
+		r = r.Sub(d)
+	}
+	return q1, r
+}
+
+// quoRem256 returns the quotient and remainder of the 256-bit value
+// (hi, lo) divided by d. It requires hi < d.Value(), which guarantees
+// that the quotient fits in 128 bits.
+func (d Divisor) quoRem256(hi, lo Uint128) (q, r Uint128) {
+	s := d.shift
+	// (hi, lo)<<s as four limbs. Since hi < d, no bits are shifted out
+	// and (u3, u2) is below the normalized divisor.
+	u3, u2 := hi.hi<<s|hi.lo>>(64-s), hi.lo<<s|lo.hi>>(64-s)
+	u1, u0 := lo.hi<<s|lo.lo>>(64-s), lo.lo<<s
+	if d.d.hi == 0 {
+		// hi < d means (u3, u2) < d, i.e. u3 == 0 and u2 < d.
+		var rem uint64
+		q.hi, rem = div2by1(u2, u1, d.n1, d.v)
+		q.lo, rem = div2by1(rem, u0, d.n1, d.v)
+		return q, Uint128{0, rem >> s}
+	}
+	q.hi, r = div3by2(u3, u2, u1, d.n1, d.n0, d.v)
+	q.lo, r = div3by2(r.hi, r.lo, u0, d.n1, d.n0, d.v)
+	return q, Uint128{r.hi >> s, r.lo>>s | r.hi<<(64-s)}
+}
+
+// mulMod returns (a * b) mod d.Value().
+func (d Divisor) mulMod(a, b Uint128) Uint128 {
+	hi, lo := a.MulFull(b)
+	if hi.Cmp(d.d) >= 0 {
+		hi = d.Mod(hi)
+	}
+	_, r := d.quoRem256(hi, lo)
+	return r
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDivisor(t *testing.T) {
+	for _, tt := range divTests {
+		d := NewDivisor(tt.v)
+		if d.Value() != tt.v {
+			t.Errorf("NewDivisor(%v).Value() = %v", tt.v, d.Value())
+		}
+		q, r := d.QuoRem(tt.u)
+		wantQ, wantR := tt.u.QuoRem(tt.v)
+		if q != wantQ || r != wantR {
+			t.Errorf("NewDivisor(%v).QuoRem(%v) = %v, %v; want %v, %v", tt.v, tt.u, q, r, wantQ, wantR)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		v := randUint128(rnd)
+		if v.IsZero() {
+			continue
+		}
+		d := NewDivisor(v)
+		for j := 0; j < 10; j++ {
+			u := randUint128(rnd)
+			q, r := d.QuoRem(u)
+			wantQ, wantR := u.QuoRem(v)
+			if q != wantQ || r != wantR {
+				t.Fatalf("NewDivisor(%v).QuoRem(%v) = %v, %v; want %v, %v", v, u, q, r, wantQ, wantR)
+			}
+			if got := d.Div(u); got != wantQ {
+				t.Fatalf("NewDivisor(%v).Div(%v) = %v; want %v", v, u, got, wantQ)
+			}
+			if got := d.Mod(u); got != wantR {
+				t.Fatalf("NewDivisor(%v).Mod(%v) = %v; want %v", v, u, got, wantR)
+			}
+		}
+	}
+}
+
+func TestNewDivisorZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewDivisor(0) did not panic")
+		}
+	}()
+	NewDivisor(uint128{})
+}
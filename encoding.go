@@ -0,0 +1,99 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"errors"
+	"strconv"
+)
+
+// MarshalText implements encoding.TextMarshaler. The text form is the
+// canonical decimal representation, as returned by String.
+//
+// TOML encoders such as github.com/BurntSushi/toml and
+// github.com/pelletier/go-toml use MarshalText and UnmarshalText, and
+// write a Uint128 as a quoted string. TOML integers are limited to
+// 64-bit signed values, so larger values must stay quoted in TOML
+// documents; decoders report unquoted ones as out of range.
+func (u Uint128) MarshalText() ([]byte, error) {
+	return u.AppendDecimal(nil), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the
+// decimal text produced by MarshalText, and hexadecimal text with a
+// 0x or 0X prefix. Leading zeros in decimal text are not taken as an
+// octal prefix. Numbers in exponent notation are rejected, with
+// strconv.ErrRange if they exceed 128 bits.
+func (u *Uint128) UnmarshalText(text []byte) error {
+	s := string(text)
+	v, err := parseText(s)
+	if err != nil {
+		// Configuration decoders pass numbers with an exponent, such as
+		// the TOML float 1e40, through as text. Report those that are
+		// too large as out of range rather than as malformed.
+		if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrSyntax {
+			if _, ferr := ParseFloatLike(s); errors.Is(ferr, strconv.ErrRange) {
+				ne.Err = strconv.ErrRange
+			}
+		}
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// parseText parses decimal, or hexadecimal with a 0x prefix.
+func parseText(s string) (Uint128, error) {
+	if len(s) >= 2 && s[0] == '0' && lower(s[1]) == 'x' {
+		return ParseUint128(s, 0)
+	}
+	return ParseUint128(s, 10)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The binary form
+// is the 16-byte big-endian encoding returned by Bytes.
+func (u Uint128) MarshalBinary() ([]byte, error) {
+	return u.AppendBytes(make([]byte, 0, 16)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It requires
+// exactly 16 bytes, as produced by MarshalBinary.
+func (u *Uint128) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return errors.New("uint128: UnmarshalBinary: invalid length")
+	}
+	*u = FromBytesBE([16]byte(data))
+	return nil
+}
+
+// gobVersion is the first byte of the GobEncode form, reserved to
+// allow changing the encoding later.
+const gobVersion byte = 1
+
+// GobEncode implements gob.GobEncoder. The encoding is a version byte
+// followed by the big-endian bytes of u without leading zeros, so that
+// small values stay small on the wire.
+func (u Uint128) GobEncode() ([]byte, error) {
+	buf := make([]byte, 1, 17)
+	buf[0] = gobVersion
+	b := u.Bytes()
+	return append(buf, b[u.LeadingZeros()/8:]...), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (u *Uint128) GobDecode(buf []byte) error {
+	if len(buf) == 0 {
+		return errors.New("uint128: GobDecode: empty input")
+	}
+	if buf[0] != gobVersion {
+		return errors.New("uint128: GobDecode: encoding version " + strconv.Itoa(int(buf[0])) + " not supported")
+	}
+	v, err := SetBytes(buf[1:])
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
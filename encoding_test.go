@@ -0,0 +1,147 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = Uint128{}
+	_ encoding.TextUnmarshaler = (*Uint128)(nil)
+
+	_ encoding.BinaryMarshaler   = Uint128{}
+	_ encoding.BinaryUnmarshaler = (*Uint128)(nil)
+
+	_ gob.GobEncoder = Uint128{}
+	_ gob.GobDecoder = (*Uint128)(nil)
+)
+
+func TestTextMarshaling(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		text, err := u.MarshalText()
+		if err != nil || string(text) != u.String() {
+			t.Fatalf("%v.MarshalText() = %q, %v", u, text, err)
+		}
+		var got uint128
+		if err := got.UnmarshalText(text); err != nil || got != u {
+			t.Fatalf("UnmarshalText(%q) = %v, %v; want %v", text, got, err, u)
+		}
+	}
+
+	for _, tt := range []struct {
+		text string
+		want uint128
+	}{
+		{"0", uint128{}},
+		{"010", uint128{0, 10}},
+		{"0xff", uint128{0, 255}},
+		{"0XFF", uint128{0, 255}},
+		{"0xffffffffffffffffffffffffffffffff", Max},
+	} {
+		var got uint128
+		if err := got.UnmarshalText([]byte(tt.text)); err != nil || got != tt.want {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v", tt.text, got, err, tt.want)
+		}
+	}
+	for _, text := range []string{"", "-1", "0x", "0b1", "1e3", "ff", " 1", "340282366920938463463374607431768211456"} {
+		got := uint128{0, 7}
+		if err := got.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %v; want error", text, got)
+		} else if got != (uint128{0, 7}) {
+			t.Errorf("failed UnmarshalText(%q) modified the value to %v", text, got)
+		}
+	}
+	for _, tt := range []struct {
+		text string
+		err  error
+	}{
+		{"1e3", strconv.ErrSyntax},
+		{"1.5", strconv.ErrSyntax},
+		{"1e40", strconv.ErrRange},
+		{"3.5e38", strconv.ErrRange},
+		{"340282366920938463463374607431768211456", strconv.ErrRange},
+	} {
+		var got uint128
+		if err := got.UnmarshalText([]byte(tt.text)); !errors.Is(err, tt.err) {
+			t.Errorf("UnmarshalText(%q) error = %v; want %v", tt.text, err, tt.err)
+		}
+	}
+}
+
+func TestBinaryMarshaling(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		data, err := u.MarshalBinary()
+		if b := u.Bytes(); err != nil || string(data) != string(b[:]) {
+			t.Fatalf("%v.MarshalBinary() = %x, %v; want %x", u, data, err, b)
+		}
+		var got uint128
+		if err := got.UnmarshalBinary(data); err != nil || got != u {
+			t.Fatalf("UnmarshalBinary(%x) = %v, %v; want %v", data, got, err, u)
+		}
+	}
+	for _, n := range []int{0, 1, 15, 17, 32} {
+		got := uint128{0, 7}
+		if err := got.UnmarshalBinary(make([]byte, n)); err == nil {
+			t.Errorf("UnmarshalBinary of %d bytes succeeded", n)
+		} else if got != (uint128{0, 7}) {
+			t.Errorf("failed UnmarshalBinary of %d bytes modified the value to %v", n, got)
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	for _, tt := range []struct {
+		u    uint128
+		want []byte
+	}{
+		{uint128{}, []byte{1}},
+		{uint128{0, 0x1ff}, []byte{1, 1, 0xff}},
+		{uint128{1, 0}, []byte{1, 1, 0, 0, 0, 0, 0, 0, 0, 0}},
+	} {
+		if got, err := tt.u.GobEncode(); err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("%v.GobEncode() = %x, %v; want %x", tt.u, got, err, tt.want)
+		}
+	}
+
+	type record struct {
+		ID    uint128
+		Sizes []uint128
+		Ptr   *uint128
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		u := randUint128(r)
+		in := record{ID: u, Sizes: []uint128{{}, randUint128(r), Max}, Ptr: &u}
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			t.Fatal(err)
+		}
+		var out record
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		if out.ID != in.ID || len(out.Sizes) != 3 || out.Sizes[1] != in.Sizes[1] || out.Sizes[2] != Max || out.Ptr == nil || *out.Ptr != u {
+			t.Fatalf("gob round trip of %+v = %+v", in, out)
+		}
+	}
+
+	for _, buf := range [][]byte{nil, {2, 1}, append([]byte{1}, make([]byte, 17)...)} {
+		var u uint128
+		if err := u.GobDecode(buf); err == nil {
+			t.Errorf("GobDecode(%x) = %v; want error", buf, u)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "sort"
+
+// Factor returns the prime factorization of u in ascending order, each
+// prime repeated according to its multiplicity. Factor returns nil for
+// 0 and 1, which have no prime factorization.
+//
+// Small factors are removed by trial division and the rest are split
+// with Pollard's rho method using Brent's cycle detection. The running
+// time grows with the square root of the second largest prime factor,
+// so inputs whose two largest factors are both near 2^64 take hours.
+func (u Uint128) Factor() []Uint128 {
+	if u.hi == 0 && u.lo < 2 {
+		return nil
+	}
+	var fs []Uint128
+	for _, p := range smallPrimes {
+		for {
+			q, r := u.Div64(p)
+			if r != 0 {
+				break
+			}
+			fs = append(fs, Uint128{0, p})
+			u = q
+		}
+	}
+	fs = factorRho(u, fs)
+	sort.Slice(fs, func(i, j int) bool { return fs[i].Cmp(fs[j]) < 0 })
+	return fs
+}
+
+// factorRho appends the prime factors of n to fs and returns the
+// result. It requires n to be free of factors in smallPrimes.
+func factorRho(n Uint128, fs []Uint128) []Uint128 {
+	if n == (Uint128{0, 1}) {
+		return fs
+	}
+	if n.IsPrime() {
+		return append(fs, n)
+	}
+	d := brent(n)
+	fs = factorRho(d, fs)
+	return factorRho(n.Div(d), fs)
+}
+
+// brent returns a non-trivial factor of the odd composite n, using
+// Pollard's rho with Brent's improvements as described in R. P. Brent,
+// "An improved Monte Carlo factorization algorithm" (BIT, 1980).
+func brent(n Uint128) Uint128 {
+	const m = 128 // gcd batch size
+	mt := NewMontgomery(n)
+	one := Uint128{0, 1}
+	for c := uint64(1); ; c++ {
+		// Iterate f(x) = x² + c in Montgomery form. Since R is coprime
+		// to n, multiplying the accumulated product q by R does not
+		// change gcd(q, n).
+		cm := mt.ToMont(Uint128{0, c})
+		f := func(x Uint128) Uint128 { return mt.MulMont(x, x).ModAdd(cm, n) }
+		y, x, ys := mt.ToMont(Uint128{0, 2}), Uint128{}, Uint128{}
+		q, g := mt.one, one
+		for r := 1; g == one; r *= 2 {
+			x = y
+			for i := 0; i < r; i++ {
+				y = f(y)
+			}
+			for k := 0; k < r && g == one; k += m {
+				ys = y
+				for i := 0; i < m && i < r-k; i++ {
+					y = f(y)
+					q = mt.MulMont(q, x.AbsDiff(y))
+				}
+				g = q.GCD(n)
+			}
+		}
+		if g == n {
+			// The batch overshot; step through it one gcd at a time.
+			for g = one; g == one; {
+				ys = f(ys)
+				g = x.AbsDiff(ys).GCD(n)
+			}
+		}
+		if g != n {
+			return g
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		want []uint64
+	}{
+		{uint128{0, 0}, nil},
+		{uint128{0, 1}, nil},
+		{uint128{0, 2}, []uint64{2}},
+		{uint128{0, 12}, []uint64{2, 2, 3}},
+		{uint128{0, 97 * 97 * 101}, []uint64{97, 97, 101}},
+		{uint128{0, 1000003 * 1000033}, []uint64{1000003, 1000033}},
+		{uint128{1, 0}, []uint64{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}},
+		// 2^128 - 1 = 3 · 5 · 17 · 257 · 641 · 65537 · 274177 · 6700417 · 67280421310721
+		{uint128{^uint64(0), ^uint64(0)}, []uint64{3, 5, 17, 257, 641, 65537, 274177, 6700417, 67280421310721}},
+	}
+	for _, tt := range tests {
+		got := tt.u.Factor()
+		if len(got) != len(tt.want) {
+			t.Errorf("%v.Factor() = %v; want %v", tt.u, got, tt.want)
+			continue
+		}
+		for i, f := range got {
+			if f != (uint128{0, tt.want[i]}) {
+				t.Errorf("%v.Factor() = %v; want %v", tt.u, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// Random products of primes of up to 40 bits, including repeats.
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		prod := uint128{0, 1}
+		var want []uint128
+		for {
+			p, _ := uint128{0, rnd.Uint64() >> (24 + rnd.Intn(30))}.NextPrime()
+			next, ok := prod.MulChecked(p)
+			if !ok {
+				break
+			}
+			prod = next
+			want = append(want, p)
+		}
+		got := prod.Factor()
+		if len(got) != len(want) {
+			t.Errorf("%v.Factor() = %v; want %d factors", prod, got, len(want))
+			continue
+		}
+		check := uint128{0, 1}
+		for j, f := range got {
+			if !f.IsPrime() || (j > 0 && got[j-1].Cmp(f) > 0) {
+				t.Errorf("%v.Factor() = %v; not sorted primes", prod, got)
+			}
+			check = check.Mul(f)
+		}
+		if check != prod {
+			t.Errorf("%v.Factor() = %v; product is %v", prod, got, check)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// Value is a Uint128 that implements flag.Value and flag.Getter, so
+// that a command-line flag can hold a 128-bit value:
+//
+//	var id uint128.Uint128
+//	flag.Var(uint128.NewValue(&id, uint128.Zero), "id", "request `ID`")
+//
+// It also has the Type method of the github.com/spf13/pflag Value
+// interface. Alternatively, since Uint128 implements
+// encoding.TextUnmarshaler, flag.TextVar and pflag.TextVar accept a
+// *Uint128 directly, with the decimal and hexadecimal forms of
+// UnmarshalText.
+type Value Uint128
+
+// NewValue sets *p to val and returns p as a *Value.
+func NewValue(p *Uint128, val Uint128) *Value {
+	*p = val
+	return (*Value)(p)
+}
+
+// Set implements flag.Value. Like the flag package's integer flags, it
+// accepts the Go integer literal syntax of ParseUint128 with base 0,
+// such as 255, 0xff, 0o377 or 0b1111_1111.
+func (v *Value) Set(s string) error {
+	u, err := ParseUint128(s, 0)
+	if err != nil {
+		return err
+	}
+	*v = Value(u)
+	return nil
+}
+
+// String implements flag.Value, returning the decimal representation.
+func (v *Value) String() string {
+	if v == nil {
+		return "0"
+	}
+	return Uint128(*v).String()
+}
+
+// Get implements flag.Getter, returning the Uint128.
+func (v *Value) Get() any { return Uint128(*v) }
+
+// Type returns "uint128", the type name shown in pflag usage messages.
+func (v *Value) Type() string { return "uint128" }
@@ -0,0 +1,88 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+// pflagValue is the Value interface of github.com/spf13/pflag.
+type pflagValue interface {
+	String() string
+	Set(string) error
+	Type() string
+}
+
+var (
+	_ flag.Getter = (*Value)(nil)
+	_ pflagValue  = (*Value)(nil)
+)
+
+func TestFlagValue(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want uint128
+		ok   bool
+	}{
+		{"255", uint128{0, 255}, true},
+		{"0xff", uint128{0, 255}, true},
+		{"0o377", uint128{0, 255}, true},
+		{"0b1111_1111", uint128{0, 255}, true},
+		{"340282366920938463463374607431768211455", Max, true},
+		{"340282366920938463463374607431768211456", uint128{0, 7}, false},
+		{"-1", uint128{0, 7}, false},
+		{"", uint128{0, 7}, false},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		var id Uint128
+		fs.Var(NewValue(&id, uint128{0, 7}), "id", "request ID")
+		err := fs.Parse([]string{"-id=" + tt.arg})
+		if (err == nil) != tt.ok || id != tt.want {
+			t.Errorf("-id=%s: got %v, %v; want %v, ok %v", tt.arg, id, err, tt.want, tt.ok)
+		}
+		if err == nil {
+			f := fs.Lookup("id")
+			if got := f.Value.String(); got != tt.want.String() {
+				t.Errorf("-id=%s: String() = %q; want %q", tt.arg, got, tt.want.String())
+			}
+			if got := f.Value.(flag.Getter).Get(); got != Uint128(tt.want) {
+				t.Errorf("-id=%s: Get() = %v; want %v", tt.arg, got, tt.want)
+			}
+		}
+	}
+
+	// Default values are shown in usage messages unless they are zero.
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var a, b Uint128
+	fs.Var(NewValue(&a, Zero), "a", "first")
+	fs.Var(NewValue(&b, uint128{1, 0}), "b", "second")
+	var usage strings.Builder
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	if s := usage.String(); strings.Contains(s, "(default 0)") || !strings.Contains(s, "(default 18446744073709551616)") {
+		t.Errorf("PrintDefaults() = %q", s)
+	}
+
+	if got := (*Value)(nil).String(); got != "0" {
+		t.Errorf("(*Value)(nil).String() = %q; want \"0\"", got)
+	}
+	if got := new(Value).Type(); got != "uint128" {
+		t.Errorf("Type() = %q; want \"uint128\"", got)
+	}
+}
+
+func TestFlagTextVar(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var id Uint128
+	fs.TextVar(&id, "id", Zero, "request ID")
+	if err := fs.Parse([]string{"-id", "0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"}); err != nil || id != Max {
+		t.Errorf("TextVar: got %v, %v; want %v", id, err, Max)
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+	"unicode/utf8"
+)
+
+// digits are the digits used by Text, in the order of big.Int.Text:
+// bases up to 36 use lowercase letters, larger ones add uppercase.
+const digits = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// MaxBase is the largest base accepted by Text and ParseUint128.
+const MaxBase = len(digits)
+
+// String returns the decimal representation of u.
+func (u Uint128) String() string {
+	return u.Text(10)
+}
+
+// Text returns the representation of u in the given base, which must
+// be between 2 and MaxBase (62). Digit values 10 to 35 are written as
+// lowercase letters 'a' to 'z' and 36 to 61 as uppercase 'A' to 'Z',
+// as with big.Int.Text. It panics for an invalid base.
+func (u Uint128) Text(base int) string {
+	var buf [128]byte
+	i := u.formatBits(&buf, base)
+	return string(buf[i:])
+}
+
+// FormatUint128 returns the representation of u in the given base.
+// It is the analog of strconv.FormatUint and equivalent to u.Text(base).
+func FormatUint128(u Uint128, base int) string {
+	return u.Text(base)
+}
+
+// AppendText appends the representation of u in the given base, as
+// generated by Text, to dst and returns the extended buffer. It does
+// not allocate unless dst lacks capacity.
+func (u Uint128) AppendText(dst []byte, base int) []byte {
+	var buf [128]byte
+	i := u.formatBits(&buf, base)
+	return append(dst, buf[i:]...)
+}
+
+// AppendDecimal appends the decimal representation of u to dst and
+// returns the extended buffer.
+func (u Uint128) AppendDecimal(dst []byte) []byte {
+	return u.AppendText(dst, 10)
+}
+
+// AppendHex appends the lowercase hexadecimal representation of u,
+// without a prefix or leading zeros, to dst and returns the extended
+// buffer.
+func (u Uint128) AppendHex(dst []byte) []byte {
+	return u.AppendText(dst, 16)
+}
+
+// SortableLen is the length of the encoding produced by
+// AppendSortable: the number of decimal digits in Max.
+const SortableLen = 39
+
+// SortableString returns the decimal representation of u zero-padded
+// to SortableLen digits, so that comparing two such strings
+// lexicographically gives the same result as comparing the values.
+// It is suitable for keys in ordered key-value stores.
+func (u Uint128) SortableString() string {
+	return string(u.AppendSortable(make([]byte, 0, SortableLen)))
+}
+
+// AppendSortable appends the representation of u produced by
+// SortableString to dst and returns the extended buffer.
+func (u Uint128) AppendSortable(dst []byte) []byte {
+	var buf [128]byte
+	i := u.formatBits(&buf, 10)
+	for j := len(buf) - i; j < SortableLen; j++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, buf[i:]...)
+}
+
+// FormatBits returns u as a string of exactly 128 '0' and '1'
+// characters, most significant bit first.
+func (u Uint128) FormatBits() string {
+	return u.FormatBitsGrouped(0, 0)
+}
+
+// FormatBitsGrouped is like FormatBits but inserts sep between groups
+// of n bits, counting from the most significant end; n = 8 gives
+// sixteen 8-bit groups. If n <= 0 or n >= 128, no separators are
+// inserted.
+func (u Uint128) FormatBitsGrouped(n int, sep rune) string {
+	b := make([]byte, 0, 128+128/max(n, 1)*utf8.UTFMax)
+	for i := 0; i < 128; i++ {
+		if n > 0 && i > 0 && i%n == 0 {
+			b = utf8.AppendRune(b, sep)
+		}
+		b = append(b, byte('0'+u.Bit(uint8(i))))
+	}
+	return string(b)
+}
+
+// FormatGrouped returns the decimal representation of u with sep
+// inserted between groups of three digits, counting from the right,
+// as in "340,282,366,920,938,463,463,374,607,431,768,211,455". With
+// sep == '_' the result is a valid Go integer literal and can be read
+// back with ParseUint128(s, 0).
+func (u Uint128) FormatGrouped(sep rune) string {
+	return string(u.AppendGrouped(nil, sep))
+}
+
+// AppendGrouped appends the representation of u produced by
+// FormatGrouped to dst and returns the extended buffer.
+func (u Uint128) AppendGrouped(dst []byte, sep rune) []byte {
+	var buf [128]byte
+	i := u.formatBits(&buf, 10)
+	digits := buf[i:]
+	for j, c := range digits {
+		if j > 0 && (len(digits)-j)%3 == 0 {
+			dst = utf8.AppendRune(dst, sep)
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// formatBits writes the digits of u in base to the end of buf and
+// returns the index of the first one. 128 bytes suffice for base 2.
+func (u Uint128) formatBits(buf *[128]byte, base int) int {
+	if base < 2 || base > MaxBase {
+		panic("uint128: invalid base")
+	}
+	b := uint64(base)
+	i := len(buf)
+	if u.hi != 0 {
+		// Peel off n digits at a time, where bb = base^n is the
+		// largest power of base that fits in a uint64.
+		bb, n := b, 1
+		for {
+			hi, lo := bits.Mul64(bb, b)
+			if hi != 0 {
+				break
+			}
+			bb, n = lo, n+1
+		}
+		for u.hi != 0 {
+			var r uint64
+			u, r = u.Div64(bb)
+			for j := 0; j < n; j++ {
+				i--
+				buf[i] = digits[r%b]
+				r /= b
+			}
+		}
+	}
+	for r := u.lo; ; {
+		i--
+		buf[i] = digits[r%b]
+		r /= b
+		if r == 0 {
+			break
+		}
+	}
+	return i
+}
+
+// GoString implements fmt.GoStringer. It returns a Go expression that
+// evaluates to u, of the form "uint128.New(0x1, 0xff)", for pasting
+// values printed with %#v back into source.
+func (u Uint128) GoString() string {
+	b := make([]byte, 0, len("uint128.New(0x, 0x)")+32)
+	b = append(b, "uint128.New(0x"...)
+	b = strconv.AppendUint(b, u.hi, 16)
+	b = append(b, ", 0x"...)
+	b = strconv.AppendUint(b, u.lo, 16)
+	return string(append(b, ')'))
+}
+
+// Format implements fmt.Formatter, in the manner of big.Int. It
+// accepts the formats 'b' (binary), 'o' (octal with 0 prefix), 'O'
+// (octal with 0o prefix), 'd' (decimal), 'x' (lowercase hexadecimal)
+// and 'X' (uppercase hexadecimal), as well as 's' and 'v', which
+// format in decimal. The '#' flag adds a 0b, 0, 0x or 0X prefix as
+// appropriate, '+' and ' ' add a sign character, and width, precision
+// (minimum number of digits) and the '-' and '0' padding flags are
+// honored. %#v formats u as GoString does.
+func (u Uint128) Format(s fmt.State, ch rune) {
+	var base int
+	switch ch {
+	case 'b':
+		base = 2
+	case 'o', 'O':
+		base = 8
+	case 'v':
+		if s.Flag('#') {
+			// fmt does not consult GoStringer for types that
+			// implement Formatter.
+			io.WriteString(s, u.GoString())
+			return
+		}
+		base = 10
+	case 'd', 's':
+		base = 10
+	case 'x', 'X':
+		base = 16
+	default:
+		fmt.Fprintf(s, "%%!%c(uint128.Uint128=%s)", ch, u.String())
+		return
+	}
+
+	sign := ""
+	switch {
+	case s.Flag('+'):
+		sign = "+"
+	case s.Flag(' '):
+		sign = " "
+	}
+
+	prefix := ""
+	if s.Flag('#') {
+		switch ch {
+		case 'b':
+			prefix = "0b"
+		case 'o':
+			prefix = "0"
+		case 'x':
+			prefix = "0x"
+		case 'X':
+			prefix = "0X"
+		}
+	}
+	if ch == 'O' {
+		prefix = "0o"
+	}
+
+	var buf [128]byte
+	i := u.formatBits(&buf, base)
+	digits := buf[i:]
+	if ch == 'X' {
+		for j, c := range digits {
+			if 'a' <= c && c <= 'f' {
+				digits[j] = c - 'a' + 'A'
+			}
+		}
+	}
+
+	// Number of characters for the three classes of padding.
+	var left, zeros, right int
+
+	precision, precisionSet := s.Precision()
+	if precisionSet {
+		switch {
+		case len(digits) < precision:
+			zeros = precision - len(digits)
+		case u.IsZero() && precision == 0:
+			return // print nothing for zero with zero precision
+		}
+	}
+
+	length := len(sign) + len(prefix) + zeros + len(digits)
+	if width, widthSet := s.Width(); widthSet && length < width {
+		switch d := width - length; {
+		case s.Flag('-'):
+			right = d
+		case s.Flag('0') && !precisionSet:
+			zeros = d
+		default:
+			left = d
+		}
+	}
+
+	writeMultiple(s, " ", left)
+	writeMultiple(s, sign, 1)
+	writeMultiple(s, prefix, 1)
+	writeMultiple(s, "0", zeros)
+	s.Write(digits)
+	writeMultiple(s, " ", right)
+}
+
+// writeMultiple writes count copies of text to s.
+func writeMultiple(s fmt.State, text string, count int) {
+	if len(text) > 0 {
+		b := []byte(text)
+		for ; count > 0; count-- {
+			s.Write(b)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		want string
+	}{
+		{uint128{}, "0"},
+		{uint128{0, 1}, "1"},
+		{uint128{0, ^uint64(0)}, "18446744073709551615"},
+		{uint128{1, 0}, "18446744073709551616"},
+		{Max, "340282366920938463463374607431768211455"},
+		// Chunks of 19 digits that are entirely zero must still be padded.
+		{uint128{0, 1e19}.Mul(uint128{1, 0}), "184467440737095516160000000000000000000"},
+		{uint128{0, 1e19}.Mul(uint128{0, 1e19}), "100000000000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.String(); got != tt.want {
+			t.Errorf("String() = %s; want %s", got, tt.want)
+		}
+	}
+	if got := fmt.Sprint(uint128{1, 0}); got != "18446744073709551616" {
+		t.Errorf("fmt.Sprint(2^64) = %s", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		if got, want := u.String(), toBig(u).String(); got != want {
+			t.Fatalf("String() = %s; want %s", got, want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		base int
+		want string
+	}{
+		{uint128{}, 2, "0"},
+		{uint128{0, 255}, 16, "ff"},
+		{uint128{0, 35}, 36, "z"},
+		{uint128{0, 61}, 62, "Z"},
+		{uint128{0, 62}, 62, "10"},
+		{uint128{1, 0}, 2, "1" + strings.Repeat("0", 64)},
+		{Max, 16, strings.Repeat("f", 32)},
+		{Max, 2, strings.Repeat("1", 128)},
+		{Max, 62, "7N42dgm5tFLK9N8MT7fHC7"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.Text(tt.base); got != tt.want {
+			t.Errorf("%v.Text(%d) = %s; want %s", tt.u, tt.base, got, tt.want)
+		}
+		if got := FormatUint128(tt.u, tt.base); got != tt.want {
+			t.Errorf("FormatUint128(%v, %d) = %s; want %s", tt.u, tt.base, got, tt.want)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		base := 2 + r.Intn(MaxBase-1)
+		s := u.Text(base)
+		if want := toBig(u).Text(base); s != want {
+			t.Fatalf("%v.Text(%d) = %s; want %s", u, base, s, want)
+		}
+		if got, err := ParseUint128(s, base); err != nil || got != u {
+			t.Fatalf("ParseUint128(%q, %d) = %v, %v; want %v", s, base, got, err, u)
+		}
+	}
+
+	for _, base := range []int{0, 1, 63} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Text(%d) did not panic", base)
+				}
+			}()
+			uint128{}.Text(base)
+		}()
+	}
+}
+
+func TestFormat(t *testing.T) {
+	u := uint128{1, 0xab} // 18446744073709551787
+	tests := []struct {
+		format string
+		u      uint128
+		want   string
+	}{
+		{"%d", u, "18446744073709551787"},
+		{"%v", u, "18446744073709551787"},
+		{"%s", u, "18446744073709551787"},
+		{"%x", u, "100000000000000ab"},
+		{"%X", u, "100000000000000AB"},
+		{"%#x", u, "0x100000000000000ab"},
+		{"%#X", u, "0X100000000000000AB"},
+		{"%o", uint128{0, 8}, "10"},
+		{"%#o", uint128{0, 8}, "010"},
+		{"%O", uint128{0, 8}, "0o10"},
+		{"%b", uint128{0, 5}, "101"},
+		{"%#b", uint128{0, 5}, "0b101"},
+		{"%+d", uint128{0, 5}, "+5"},
+		{"% d", uint128{0, 5}, " 5"},
+		{"%6d", uint128{0, 5}, "     5"},
+		{"%-6d|", uint128{0, 5}, "5     |"},
+		{"%06d", uint128{0, 5}, "000005"},
+		{"%#08x", uint128{0, 255}, "0x0000ff"},
+		{"%.4d", uint128{0, 5}, "0005"},
+		{"%8.4d", uint128{0, 5}, "    0005"},
+		{"%08.4d", uint128{0, 5}, "    0005"},
+		{"%.0d", uint128{}, ""},
+		{"%.d", uint128{}, ""},
+		{"%d", uint128{}, "0"},
+		{"%x", Max, strings.Repeat("f", 32)},
+		{"%q", uint128{0, 5}, "%!q(uint128.Uint128=5)"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.u); got != tt.want {
+			t.Errorf("Sprintf(%q, %s) = %q; want %q", tt.format, tt.u, got, tt.want)
+		}
+	}
+	if got := fmt.Sprintf("%x", []uint128{{0, 10}, {1, 0}}); got != "[a 10000000000000000]" {
+		t.Errorf("Sprintf(%%x, slice) = %q", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		b := toBig(u)
+		for _, f := range []string{"%d", "%x", "%X", "%#x", "%o", "%O", "%b", "%50d", "%-50x|", "%050d", "%.45d"} {
+			if got, want := fmt.Sprintf(f, u), fmt.Sprintf(f, b); got != want {
+				t.Fatalf("Sprintf(%q, %v) = %q; want %q", f, u, got, want)
+			}
+		}
+	}
+}
+
+func TestAppendText(t *testing.T) {
+	u := uint128{1, 0xab}
+	dst := []byte("x=")
+	if got := string(u.AppendDecimal(dst)); got != "x=18446744073709551787" {
+		t.Errorf("AppendDecimal = %q", got)
+	}
+	if got := string(u.AppendHex(dst)); got != "x=100000000000000ab" {
+		t.Errorf("AppendHex = %q", got)
+	}
+	if got := string(u.AppendText(dst, 36)); got != "x="+u.Text(36) {
+		t.Errorf("AppendText(36) = %q; want %q", got, "x="+u.Text(36))
+	}
+
+	buf := make([]byte, 0, 128)
+	if n := testing.AllocsPerRun(100, func() {
+		buf = Max.AppendDecimal(buf[:0])
+		buf = Max.AppendHex(buf[:0])
+		buf = Max.AppendText(buf[:0], 2)
+	}); n != 0 {
+		t.Errorf("Append functions allocate %v times; want 0", n)
+	}
+}
+
+func TestFormatGrouped(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		sep  rune
+		want string
+	}{
+		{uint128{}, ',', "0"},
+		{uint128{0, 999}, ',', "999"},
+		{uint128{0, 1000}, ',', "1,000"},
+		{uint128{0, 123456}, ' ', "123 456"},
+		{uint128{0, 1234567}, '_', "1_234_567"},
+		{uint128{0, 1234567}, '\u202f', "1\u202f234\u202f567"}, // multi-byte separator
+		{Max, ',', "340,282,366,920,938,463,463,374,607,431,768,211,455"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.FormatGrouped(tt.sep); got != tt.want {
+			t.Errorf("%v.FormatGrouped(%q) = %q; want %q", tt.u, tt.sep, got, tt.want)
+		}
+	}
+	if got := string(Max.AppendGrouped([]byte("n="), '_')); got != "n=340_282_366_920_938_463_463_374_607_431_768_211_455" {
+		t.Errorf("AppendGrouped = %q", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		s := u.FormatGrouped('_')
+		if got, err := ParseUint128(s, 0); err != nil || got != u {
+			t.Fatalf("ParseUint128(%q, 0) = %v, %v; want %v", s, got, err, u)
+		}
+	}
+}
+
+func TestGoString(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		want string
+	}{
+		{uint128{}, "uint128.New(0x0, 0x0)"},
+		{uint128{1, 0xff}, "uint128.New(0x1, 0xff)"},
+		{Max, "uint128.New(0xffffffffffffffff, 0xffffffffffffffff)"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.GoString(); got != tt.want {
+			t.Errorf("GoString() = %s; want %s", got, tt.want)
+		}
+		if got := fmt.Sprintf("%#v", tt.u); got != tt.want {
+			t.Errorf("Sprintf(%%#v) = %s; want %s", got, tt.want)
+		}
+	}
+	type pair struct{ A, B uint128 }
+	if got, want := fmt.Sprintf("%#v", []uint128{{0, 1}}), "[]uint128.Uint128{uint128.New(0x0, 0x1)}"; got != want {
+		t.Errorf("Sprintf(%%#v, slice) = %s; want %s", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", pair{One, Max}), "{1 340282366920938463463374607431768211455}"; got != want {
+		t.Errorf("Sprintf(%%v, struct) = %s; want %s", got, want)
+	}
+}
+
+func TestSortable(t *testing.T) {
+	if got, want := (uint128{}).SortableString(), strings.Repeat("0", 39); got != want {
+		t.Errorf("SortableString(0) = %s; want %s", got, want)
+	}
+	if got, want := Max.SortableString(), Max.String(); got != want {
+		t.Errorf("SortableString(Max) = %s; want %s", got, want)
+	}
+	if got := string(One.AppendSortable([]byte("k/"))); got != "k/"+strings.Repeat("0", 38)+"1" {
+		t.Errorf("AppendSortable = %s", got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	prev, prevS := uint128{}, uint128{}.SortableString()
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		s := u.SortableString()
+		if len(s) != SortableLen {
+			t.Fatalf("len(%v.SortableString()) = %d", u, len(s))
+		}
+		if got, want := strings.Compare(s, prevS), u.Cmp(prev); got != want {
+			t.Fatalf("Compare(%s, %s) = %d; want %d", s, prevS, got, want)
+		}
+		if got, err := ParseSortable(s); err != nil || got != u {
+			t.Fatalf("ParseSortable(%s) = %v, %v; want %v", s, got, err, u)
+		}
+		prev, prevS = u, s
+	}
+
+	for _, s := range []string{"", "1", strings.Repeat("0", 40), strings.Repeat("0", 38) + "x", "+" + strings.Repeat("0", 38), strings.Repeat("9", 39)} {
+		if _, err := ParseSortable(s); err == nil {
+			t.Errorf("ParseSortable(%q) succeeded", s)
+		} else if err.(*strconv.NumError).Func != "ParseSortable" {
+			t.Errorf("ParseSortable(%q) error = %v", s, err)
+		}
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	u := uint128{0x8000000000000000, 5}
+	want := "1" + strings.Repeat("0", 124) + "101"
+	if got := u.FormatBits(); got != want {
+		t.Errorf("FormatBits() = %s; want %s", got, want)
+	}
+	if got, want := (uint128{0xff00000000000000, 0}).FormatBitsGrouped(8, '_'), "11111111"+strings.Repeat("_00000000", 15); got != want {
+		t.Errorf("FormatBitsGrouped(8, '_') = %s; want %s", got, want)
+	}
+	if got := u.FormatBitsGrouped(128, '_'); got != want {
+		t.Errorf("FormatBitsGrouped(128, '_') = %s; want %s", got, want)
+	}
+	if got := u.FormatBitsGrouped(5, ' '); len(got) != 128+25 {
+		t.Errorf("len(FormatBitsGrouped(5, ' ')) = %d; want %d", len(got), 128+25)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		s := u.FormatBits()
+		if want := fmt.Sprintf("%0128b", u); s != want {
+			t.Fatalf("%v.FormatBits() = %s; want %s", u, s, want)
+		}
+		for _, f := range []string{s, u.FormatBitsGrouped(4, '_'), u.FormatBitsGrouped(1+r.Intn(16), ' ')} {
+			if got, err := ParseBits(f); err != nil || got != u {
+				t.Fatalf("ParseBits(%q) = %v, %v; want %v", f, got, err, u)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// Integer is a constraint that permits any integer type, like
+// golang.org/x/exp/constraints.Integer.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// From returns x converted to a Uint128 with the semantics of a Go
+// integer conversion: a negative x is sign-extended, so that the
+// result is x modulo 2^128 (for example, From(-1) is 2^128-1).
+// Use TryFrom to reject negative values instead.
+func From[T Integer](x T) Uint128 {
+	if x < 0 {
+		return Uint128{^uint64(0), uint64(x)}
+	}
+	return Uint128{0, uint64(x)}
+}
+
+// TryFrom returns x converted to a Uint128, or 0 and ok == false if x
+// is negative.
+func TryFrom[T Integer](x T) (u Uint128, ok bool) {
+	if x < 0 {
+		return Uint128{}, false
+	}
+	return Uint128{0, uint64(x)}, true
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFrom(t *testing.T) {
+	ones := uint128{^uint64(0), ^uint64(0)}
+	type myInt int16
+	tests := []struct {
+		name string
+		got  uint128
+		want uint128
+	}{
+		{"int(0)", From(0), uint128{}},
+		{"int(42)", From(42), uint128{0, 42}},
+		{"int(-1)", From(-1), ones},
+		{"int8(-128)", From(int8(math.MinInt8)), uint128{^uint64(0), ^uint64(127)}},
+		{"int64 min", From(int64(math.MinInt64)), uint128{^uint64(0), 1 << 63}},
+		{"int64 max", From(int64(math.MaxInt64)), uint128{0, math.MaxInt64}},
+		{"uint8(255)", From(uint8(255)), uint128{0, 255}},
+		{"uint64 max", From(uint64(math.MaxUint64)), uint128{0, ^uint64(0)}},
+		{"uintptr", From(uintptr(7)), uint128{0, 7}},
+		{"myInt(-2)", From(myInt(-2)), ones.SubOne()},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("From(%s) = %#x; want %#x", tt.name, tt.got, tt.want)
+		}
+	}
+	// Wrapping through From agrees with two's-complement negation.
+	if got := From(-12345); got != From(12345).Neg() {
+		t.Errorf("From(-12345) = %#x; want %#x", got, From(12345).Neg())
+	}
+}
+
+func TestTryFrom(t *testing.T) {
+	if u, ok := TryFrom(-1); ok || !u.IsZero() {
+		t.Errorf("TryFrom(-1) = %v, %v; want 0, false", u, ok)
+	}
+	if u, ok := TryFrom(int8(math.MinInt8)); ok || !u.IsZero() {
+		t.Errorf("TryFrom(int8(-128)) = %v, %v; want 0, false", u, ok)
+	}
+	if u, ok := TryFrom(int32(7)); !ok || u != (uint128{0, 7}) {
+		t.Errorf("TryFrom(int32(7)) = %v, %v; want 7, true", u, ok)
+	}
+	if u, ok := TryFrom(uint64(math.MaxUint64)); !ok || u != (uint128{0, ^uint64(0)}) {
+		t.Errorf("TryFrom(MaxUint64) = %v, %v; want 2^64-1, true", u, ok)
+	}
+}
@@ -1,3 +1,16 @@
 module uint128
 
-go 1.19
+go 1.23.0
+
+require github.com/jackc/pgx/v5 v5.7.6
+
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/pelletier/go-toml/v2 v2.2.4
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	go.mongodb.org/mongo-driver/v2 v2.8.2
+	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
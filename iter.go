@@ -0,0 +1,47 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "iter"
+
+// NextSetBit returns the number of the first set bit of u at or after
+// bit from, where bit 0 is the most significant bit, or -1 if there is
+// none. Negative values of from are treated as 0.
+func (u Uint128) NextSetBit(from int) int {
+	u = u.And(Mask6(from).Not())
+	if u.IsZero() {
+		return -1
+	}
+	return u.LeadingZeros()
+}
+
+// Bits returns an iterator over the numbers of the set bits of u in
+// increasing order, where bit 0 is the most significant bit, for use
+// of u as a 128-entry bitmap.
+func (u Uint128) Bits() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for v := u; !v.IsZero(); {
+			i := v.LeadingZeros()
+			if !yield(i) {
+				return
+			}
+			v = v.ClearBit(uint8(i))
+		}
+	}
+}
+
+// Submasks returns an iterator over all values whose set bits are a
+// subset of those of u, in decreasing numeric order from u itself down
+// to 0. A u with k set bits yields 2^k values, so callers iterating
+// masks with many bits set must stop early.
+func (u Uint128) Submasks() iter.Seq[Uint128] {
+	return func(yield func(Uint128) bool) {
+		for s := u; ; s = s.SubOne().And(u) {
+			if !yield(s) || s.IsZero() {
+				return
+			}
+		}
+	}
+}
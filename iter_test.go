@@ -0,0 +1,90 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitsIter(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		want []int
+	}{
+		{uint128{0, 0}, nil},
+		{uint128{1 << 63, 0}, []int{0}},
+		{uint128{0, 1}, []int{127}},
+		{uint128{1<<63 | 1, 1<<63 | 1}, []int{0, 63, 64, 127}},
+		{uint128{0, 0b1011}, []int{124, 126, 127}},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(tt.u.Bits())
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v.Bits() = %v; want %v", tt.u, got, tt.want)
+		}
+		// NextSetBit visits the same positions.
+		var next []int
+		for i := tt.u.NextSetBit(0); i >= 0; i = tt.u.NextSetBit(i + 1) {
+			next = append(next, i)
+		}
+		if !slices.Equal(next, tt.want) {
+			t.Errorf("%v: NextSetBit walk = %v; want %v", tt.u, next, tt.want)
+		}
+	}
+
+	ones := uint128{^uint64(0), ^uint64(0)}
+	if n := len(slices.Collect(ones.Bits())); n != 128 {
+		t.Errorf("len(ones.Bits()) = %d; want 128", n)
+	}
+	for i := range ones.Bits() {
+		if i == 5 {
+			break // stopping early must not panic
+		}
+	}
+	for _, tt := range []struct{ from, want int }{{-5, 0}, {0, 0}, {127, 127}, {128, -1}, {1000, -1}} {
+		if got := ones.NextSetBit(tt.from); got != tt.want {
+			t.Errorf("ones.NextSetBit(%d) = %d; want %d", tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestSubmasks(t *testing.T) {
+	tests := []struct {
+		mask uint128
+		want []uint128
+	}{
+		{uint128{0, 0}, []uint128{{0, 0}}},
+		{uint128{0, 1}, []uint128{{0, 1}, {0, 0}}},
+		{uint128{0, 0b101}, []uint128{{0, 0b101}, {0, 0b100}, {0, 0b001}, {0, 0}}},
+		{uint128{1, 1}, []uint128{{1, 1}, {1, 0}, {0, 1}, {0, 0}}},
+		{uint128{1 << 63, 0}, []uint128{{1 << 63, 0}, {0, 0}}},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(tt.mask.Submasks())
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%#x.Submasks() = %#x; want %#x", tt.mask, got, tt.want)
+		}
+	}
+
+	mask := uint128{0x8000000000000101, 0x4000000000000402}
+	seen := map[uint128]bool{}
+	for s := range mask.Submasks() {
+		if s.And(mask.Not()) != (uint128{}) || seen[s] {
+			t.Errorf("%#x.Submasks() yielded %#x", mask, s)
+		}
+		seen[s] = true
+	}
+	if n := len(seen); n != 1<<mask.OnesCount() {
+		t.Errorf("%#x.Submasks() yielded %d values; want %d", mask, n, 1<<mask.OnesCount())
+	}
+
+	n := 0
+	for range (uint128{^uint64(0), ^uint64(0)}).Submasks() {
+		if n++; n == 10 {
+			break
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "errors"
+
+// A JSONFormat selects how MarshalJSON represents a Uint128.
+type JSONFormat uint8
+
+const (
+	// JSONDecimal is a quoted decimal string, such as "255". It is the
+	// default, because many JSON decoders read numbers into float64 and
+	// would silently lose precision above 2^53.
+	JSONDecimal JSONFormat = iota
+
+	// JSONHex is a quoted hexadecimal string with a 0x prefix, such
+	// as "0xff".
+	JSONHex
+
+	// JSONNumber is an unquoted decimal number, such as 255. Only use
+	// it when every consumer decodes numbers exactly.
+	JSONNumber
+)
+
+// JSONMarshalFormat is the representation produced by MarshalJSON. It
+// is a process-wide setting and should be set only during
+// initialization, before any concurrent marshaling. UnmarshalJSON
+// accepts all formats regardless of this setting.
+var JSONMarshalFormat = JSONDecimal
+
+// MarshalJSON implements json.Marshaler, using the representation
+// selected by JSONMarshalFormat.
+func (u Uint128) MarshalJSON() ([]byte, error) {
+	return u.appendJSON(make([]byte, 0, 41), JSONMarshalFormat), nil
+}
+
+func (u Uint128) appendJSON(b []byte, f JSONFormat) []byte {
+	switch f {
+	case JSONHex:
+		b = append(b, `"0x`...)
+		return append(u.AppendHex(b), '"')
+	case JSONNumber:
+		return u.AppendDecimal(b)
+	}
+	b = append(b, '"')
+	return append(u.AppendDecimal(b), '"')
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts any of the
+// JSONFormat representations: a quoted decimal string, a quoted
+// hexadecimal string with a 0x prefix, or an unquoted integer. As is
+// conventional, the JSON null value leaves u unchanged.
+func (u *Uint128) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	var (
+		v   Uint128
+		err error
+	)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		v, err = parseText(s[1 : len(s)-1])
+	} else {
+		v, err = ParseUint128(s, 10)
+	}
+	if err != nil {
+		return errors.New("uint128: cannot unmarshal JSON " + s + " into Uint128: " + err.Error())
+	}
+	*u = v
+	return nil
+}
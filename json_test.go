@@ -0,0 +1,65 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	defer func(f JSONFormat) { JSONMarshalFormat = f }(JSONMarshalFormat)
+
+	type doc struct {
+		N uint128  `json:"n"`
+		P *uint128 `json:"p,omitempty"`
+	}
+	u := uint128{1, 0xff} // 18446744073709551871
+	for _, tt := range []struct {
+		f    JSONFormat
+		want string
+	}{
+		{JSONDecimal, `{"n":"18446744073709551871"}`},
+		{JSONHex, `{"n":"0x100000000000000ff"}`},
+		{JSONNumber, `{"n":18446744073709551871}`},
+	} {
+		JSONMarshalFormat = tt.f
+		b, err := json.Marshal(doc{N: u})
+		if err != nil || string(b) != tt.want {
+			t.Errorf("format %d: Marshal = %s, %v; want %s", tt.f, b, err, tt.want)
+		}
+		var d doc
+		if err := json.Unmarshal(b, &d); err != nil || d.N != u {
+			t.Errorf("format %d: Unmarshal(%s) = %v, %v; want %v", tt.f, b, d.N, err, u)
+		}
+	}
+
+	JSONMarshalFormat = JSONDecimal
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		u := randUint128(r)
+		b, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got uint128
+		if err := json.Unmarshal(b, &got); err != nil || got != u {
+			t.Fatalf("Unmarshal(%s) = %v, %v; want %v", b, got, err, u)
+		}
+	}
+
+	d := doc{N: uint128{0, 7}}
+	if err := json.Unmarshal([]byte(`{"n":null,"p":"0XFF"}`), &d); err != nil || d.N != (uint128{0, 7}) || d.P == nil || *d.P != (uint128{0, 255}) {
+		t.Errorf("Unmarshal with null and pointer = %+v, %v", d, err)
+	}
+
+	for _, in := range []string{`""`, `"-1"`, `-1`, `1.5`, `1e3`, `"ff"`, `true`, `"340282366920938463463374607431768211456"`, `[1]`} {
+		var got uint128
+		if err := json.Unmarshal([]byte(in), &got); err == nil {
+			t.Errorf("Unmarshal(%s) = %v; want error", in, got)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.25 && !go1.27 && goexperiment.jsonv2
+
+// Go 1.25 and 1.26 provide encoding/json/v2 under GOEXPERIMENT=jsonv2.
+// This file repeats json_v2.go for them, because vet requires files
+// using the package to declare the Go version that made it standard.
+
+package uint128
+
+import "encoding/json/jsontext"
+
+// MarshalJSONTo implements the encoding/json/v2 MarshalerTo interface.
+// It writes the same representation as MarshalJSON, selected by
+// JSONMarshalFormat, without allocating.
+func (u Uint128) MarshalJSONTo(enc *jsontext.Encoder) error {
+	var buf [48]byte // quotes + "0x" + 39 digits
+	return enc.WriteValue(u.appendJSON(buf[:0], JSONMarshalFormat))
+}
+
+// UnmarshalJSONFrom implements the encoding/json/v2 UnmarshalerFrom
+// interface. It accepts the same inputs as UnmarshalJSON.
+func (u *Uint128) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	val, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalJSON(val)
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.27
+
+package uint128
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = Uint128{}
+	_ json.UnmarshalerFrom = (*Uint128)(nil)
+)
+
+func TestJSONv2(t *testing.T) {
+	defer func(f JSONFormat) { JSONMarshalFormat = f }(JSONMarshalFormat)
+
+	type doc struct {
+		N []uint128 `json:"n"`
+	}
+	in := doc{N: []uint128{{}, {1, 0xff}, Max}}
+	for _, tt := range []struct {
+		f    JSONFormat
+		want string
+	}{
+		{JSONDecimal, `{"n":["0","18446744073709551871","340282366920938463463374607431768211455"]}`},
+		{JSONHex, `{"n":["0x0","0x100000000000000ff","0xffffffffffffffffffffffffffffffff"]}`},
+		{JSONNumber, `{"n":[0,18446744073709551871,340282366920938463463374607431768211455]}`},
+	} {
+		JSONMarshalFormat = tt.f
+		b, err := json.Marshal(in)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("format %d: Marshal = %s, %v; want %s", tt.f, b, err, tt.want)
+			continue
+		}
+		var out doc
+		if err := json.Unmarshal(b, &out); err != nil || len(out.N) != 3 || out.N[1] != in.N[1] || out.N[2] != Max {
+			t.Errorf("format %d: Unmarshal(%s) = %v, %v", tt.f, b, out.N, err)
+		}
+	}
+
+	for _, s := range []string{`"1x"`, `-1`, `{}`, `1.5`} {
+		var u uint128
+		if err := json.Unmarshal([]byte(s), &u); err == nil {
+			t.Errorf("Unmarshal(%s) = %v; want error", s, u)
+		}
+	}
+
+	JSONMarshalFormat = JSONDecimal
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+	if n := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		enc.Reset(&buf)
+		if err := Max.MarshalJSONTo(enc); err != nil {
+			t.Fatal(err)
+		}
+	}); n != 0 {
+		t.Errorf("MarshalJSONTo allocates %v times; want 0", n)
+	}
+}
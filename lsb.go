@@ -0,0 +1,69 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// This file provides the bit-numbering API in the convention used by
+// math/bits and math/big, where bit 0 is the least significant bit.
+// Each function mirrors one whose bit numbers start from the most
+// significant bit, and carries an LSB suffix. LowMask is already
+// LSB-oriented and has no counterpart here.
+
+// msb converts the LSB-numbered bit i to its MSB-numbered equivalent,
+// mapping out-of-range bits to an out-of-range bit.
+func msb(i uint8) uint8 {
+	if i > 127 {
+		return 128
+	}
+	return 127 - i
+}
+
+// BitLSB returns the value (0 or 1) of bit i of u, where bit 0 is the
+// least significant bit. It returns 0 for i >= 128.
+func (u Uint128) BitLSB(i uint8) uint { return u.Bit(msb(i)) }
+
+// SetBitLSB returns a copy of u with bit i set, where bit 0 is the
+// least significant bit. For i >= 128 it returns u unchanged.
+func (u Uint128) SetBitLSB(i uint8) Uint128 { return u.SetBit(msb(i)) }
+
+// ClearBitLSB returns a copy of u with bit i cleared, where bit 0 is
+// the least significant bit. For i >= 128 it returns u unchanged.
+func (u Uint128) ClearBitLSB(i uint8) Uint128 { return u.ClearBit(msb(i)) }
+
+// ToggleBitLSB returns a copy of u with bit i inverted, where bit 0 is
+// the least significant bit. For i >= 128 it returns u unchanged.
+func (u Uint128) ToggleBitLSB(i uint8) Uint128 { return u.ToggleBit(msb(i)) }
+
+// MaskLSB returns a Uint128 bitmask with bits lo through hi inclusive
+// set, where bit 0 is the least significant bit.
+// It panics unless lo <= hi < 128.
+func MaskLSB(hi, lo uint8) Uint128 {
+	validLSB(hi, lo)
+	return Mask(127-hi, 127-lo)
+}
+
+// ExtractRangeLSB returns the bit field of u spanning bits hi down to
+// lo inclusive, where bit 0 is the least significant bit, shifted down
+// so that bit lo lands in the least significant position.
+// It panics unless lo <= hi < 128.
+func (u Uint128) ExtractRangeLSB(hi, lo uint8) Uint128 {
+	validLSB(hi, lo)
+	return u.ExtractRange(127-hi, 127-lo)
+}
+
+// InsertRangeLSB returns a copy of u with the bit field spanning bits
+// hi down to lo inclusive replaced by the low-order bits of v, where
+// bit 0 is the least significant bit.
+// It panics unless lo <= hi < 128.
+func (u Uint128) InsertRangeLSB(hi, lo uint8, v Uint128) Uint128 {
+	validLSB(hi, lo)
+	return u.InsertRange(127-hi, 127-lo, v)
+}
+
+// validLSB panics unless lo <= hi < 128.
+func validLSB(hi, lo uint8) {
+	if lo > hi || hi > 127 {
+		panic("uint128: invalid bit range")
+	}
+}
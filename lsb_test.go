@@ -0,0 +1,66 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "testing"
+
+func TestLSBBits(t *testing.T) {
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	b := toBig(u)
+	for i := 0; i < 256; i++ {
+		i8 := uint8(i)
+		want := b.Bit(i)
+		if i >= 128 {
+			want = 0
+		}
+		if got := u.BitLSB(i8); got != want {
+			t.Errorf("%v.BitLSB(%d) = %d; want %d", u, i, got, want)
+		}
+		mask := uint128{0, 1}.Lsh(uint(i))
+		if got := u.SetBitLSB(i8); got != u.Or(mask) {
+			t.Errorf("%v.SetBitLSB(%d) = %#x; want %#x", u, i, got, u.Or(mask))
+		}
+		if got := u.ClearBitLSB(i8); got != u.And(mask.Not()) {
+			t.Errorf("%v.ClearBitLSB(%d) = %#x; want %#x", u, i, got, u.And(mask.Not()))
+		}
+		if got := u.ToggleBitLSB(i8); got != u.Xor(mask) {
+			t.Errorf("%v.ToggleBitLSB(%d) = %#x; want %#x", u, i, got, u.Xor(mask))
+		}
+	}
+}
+
+func TestLSBRanges(t *testing.T) {
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	tests := []struct {
+		hi, lo uint8
+		mask   uint128
+		field  uint128
+	}{
+		{127, 0, uint128{^uint64(0), ^uint64(0)}, u},
+		{7, 0, uint128{0, 0xff}, uint128{0, 0x10}},
+		{11, 4, uint128{0, 0xff0}, uint128{0, 0x21}},
+		{67, 60, uint128{0xf, 0xf << 60}, uint128{0, 0xff}},
+		{127, 64, uint128{^uint64(0), 0}, uint128{0, 0x0123456789abcdef}},
+		{0, 0, uint128{0, 1}, uint128{0, 0}},
+	}
+	for _, tt := range tests {
+		if got := MaskLSB(tt.hi, tt.lo); got != tt.mask {
+			t.Errorf("MaskLSB(%d, %d) = %#x; want %#x", tt.hi, tt.lo, got, tt.mask)
+		}
+		if got := u.ExtractRangeLSB(tt.hi, tt.lo); got != tt.field {
+			t.Errorf("%v.ExtractRangeLSB(%d, %d) = %#x; want %#x", u, tt.hi, tt.lo, got, tt.field)
+		}
+		ones := uint128{^uint64(0), ^uint64(0)}
+		if got := (uint128{}).InsertRangeLSB(tt.hi, tt.lo, ones); got != tt.mask {
+			t.Errorf("0.InsertRangeLSB(%d, %d, ones) = %#x; want %#x", tt.hi, tt.lo, got, tt.mask)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MaskLSB(0, 1) did not panic")
+		}
+	}()
+	MaskLSB(0, 1)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// A Montgomery holds the precomputed constants for Montgomery
+// multiplication modulo a fixed odd modulus m, with R = 2^128.
+//
+// Values are converted into Montgomery form (x*R mod m) with ToMont,
+// multiplied and exponentiated there with MulMont and ExpMont, and
+// converted back with FromMont. Each MulMont costs three 128-bit
+// multiplications and no division, which makes long chains of modular
+// multiplications much faster than ModMul.
+//
+// The zero Montgomery is not usable; create one with NewMontgomery.
+type Montgomery struct {
+	m    Uint128 // the modulus
+	mInv Uint128 // -m^-1 mod R
+	one  Uint128 // R mod m, i.e. 1 in Montgomery form
+	r2   Uint128 // R^2 mod m
+}
+
+// NewMontgomery returns the Montgomery context for the modulus m.
+// It panics if m is even (including zero).
+func NewMontgomery(m Uint128) Montgomery {
+	if m.lo&1 == 0 {
+		panic("uint128: Montgomery modulus must be odd")
+	}
+	// Newton's iteration x' = x*(2 - m*x) doubles the number of
+	// correct low bits of x = m^-1 mod R; m*m == 1 mod 8 gives the
+	// first three, so five steps reach 96 and a sixth covers 128.
+	x := m
+	for i := 0; i < 6; i++ {
+		x = x.Mul(Uint128{0, 2}.Sub(m.Mul(x)))
+	}
+	one := Max.Mod(m).ModAdd(One, m)
+	return Montgomery{
+		m:    m,
+		mInv: x.Neg(),
+		one:  one,
+		r2:   one.ModMul(one, m),
+	}
+}
+
+// Modulus returns the modulus of the context.
+func (mt Montgomery) Modulus() Uint128 { return mt.m }
+
+// ToMont returns x in Montgomery form, x*R mod m.
+// The input need not be reduced modulo m.
+func (mt Montgomery) ToMont(x Uint128) Uint128 {
+	return mt.MulMont(x.reduce(mt.m), mt.r2)
+}
+
+// FromMont converts x out of Montgomery form, returning x*R^-1 mod m.
+func (mt Montgomery) FromMont(x Uint128) Uint128 {
+	return mt.redc(Uint128{}, x)
+}
+
+// MulMont returns the Montgomery product x*y*R^-1 mod m of two values
+// in Montgomery form. Both operands must be reduced modulo m.
+func (mt Montgomery) MulMont(x, y Uint128) Uint128 {
+	return mt.redc(x.MulFull(y))
+}
+
+// ExpMont returns x**e in Montgomery form for x in Montgomery form.
+func (mt Montgomery) ExpMont(x, e Uint128) Uint128 {
+	r := mt.one
+	for !e.IsZero() {
+		if e.lo&1 != 0 {
+			r = mt.MulMont(r, x)
+		}
+		e = e.Rsh(1)
+		if !e.IsZero() {
+			x = mt.MulMont(x, x)
+		}
+	}
+	return r
+}
+
+// redc returns (hi, lo)*R^-1 mod m for (hi, lo) < m*R.
+func (mt Montgomery) redc(hi, lo Uint128) Uint128 {
+	// Adding q*m, with q chosen so that the low half cancels,
+	// makes the 256-bit sum exactly divisible by R.
+	q := lo.Mul(mt.mInv)
+	qmHi, qmLo := q.MulFull(mt.m)
+	_, c := lo.AddCarry(qmLo, 0)
+	t, c := hi.AddCarry(qmHi, c)
+	if c != 0 || t.Cmp(mt.m) >= 0 {
+		t = t.Sub(mt.m)
+	}
+	return t
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMontgomery(t *testing.T) {
+	moduli := []uint128{
+		{0, 1}, {0, 3}, {0, 1000000007}, {0, ^uint64(0)}, {1, 1},
+		{^uint64(0), ^uint64(0) - 158}, {^uint64(0), ^uint64(0)},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for len(moduli) < 40 {
+		moduli = append(moduli, randUint128(rnd).Or(uint128{0, 1}))
+	}
+	for _, m := range moduli {
+		mt := NewMontgomery(m)
+		if mt.Modulus() != m {
+			t.Errorf("NewMontgomery(%v).Modulus() = %v", m, mt.Modulus())
+		}
+		for i := 0; i < 20; i++ {
+			a, b, e := randUint128(rnd), randUint128(rnd), randUint128(rnd)
+			am, bm := mt.ToMont(a), mt.ToMont(b)
+			if got := mt.FromMont(am); got != a.Mod(m) {
+				t.Errorf("m=%v: FromMont(ToMont(%v)) = %v; want %v", m, a, got, a.Mod(m))
+			}
+			if got, want := mt.FromMont(mt.MulMont(am, bm)), a.ModMul(b, m); got != want {
+				t.Errorf("m=%v: MulMont(%v, %v) = %v; want %v", m, a, b, got, want)
+			}
+			if got, want := mt.FromMont(mt.ExpMont(am, e)), a.ModExp(e, m); got != want {
+				t.Errorf("m=%v: ExpMont(%v, %v) = %v; want %v", m, a, e, got, want)
+			}
+		}
+	}
+}
+
+func TestNewMontgomeryEven(t *testing.T) {
+	for _, m := range []uint128{{0, 0}, {0, 2}, {1, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewMontgomery(%v) did not panic", m)
+				}
+			}()
+			NewMontgomery(m)
+		}()
+	}
+}
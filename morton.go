@@ -0,0 +1,117 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// Interleave returns the 2D Morton (Z-order) code of (x, y): bit i of x
+// becomes bit 2i of the result and bit i of y becomes bit 2i+1, where
+// bit 0 is the least significant bit.
+func Interleave(x, y uint64) Uint128 {
+	return Uint128{
+		spread2(x>>32) | spread2(y>>32)<<1,
+		spread2(x&0xffffffff) | spread2(y&0xffffffff)<<1,
+	}
+}
+
+// Deinterleave returns the coordinates encoded in the 2D Morton code u;
+// it is the inverse of Interleave.
+func (u Uint128) Deinterleave() (x, y uint64) {
+	x = compact2(u.hi)<<32 | compact2(u.lo)
+	y = compact2(u.hi>>1)<<32 | compact2(u.lo>>1)
+	return x, y
+}
+
+// mortonMask3 has every third bit set, starting from bit 0 (the least
+// significant), for a total of 42 bits.
+var mortonMask3 = Uint128{0x0924924924924924, 0x9249249249249249}
+
+// Interleave3 returns the 3D Morton code of (x, y, z): bit i of x, y
+// and z becomes bit 3i, 3i+1 and 3i+2 of the result respectively, where
+// bit 0 is the least significant bit. Only the low 42 bits of each
+// coordinate fit in the 126-bit code; higher bits are ignored.
+func Interleave3(x, y, z uint64) Uint128 {
+	return Uint128{0, x}.DepositBits(mortonMask3).
+		Or(Uint128{0, y}.DepositBits(mortonMask3.Lsh(1))).
+		Or(Uint128{0, z}.DepositBits(mortonMask3.Lsh(2)))
+}
+
+// Deinterleave3 returns the coordinates encoded in the 3D Morton code
+// u; it is the inverse of Interleave3 for 42-bit coordinates.
+func (u Uint128) Deinterleave3() (x, y, z uint64) {
+	x = u.ExtractBits(mortonMask3).lo
+	y = u.ExtractBits(mortonMask3.Lsh(1)).lo
+	z = u.ExtractBits(mortonMask3.Lsh(2)).lo
+	return x, y, z
+}
+
+// spread2 spaces out the low 32 bits of v to the even bit positions.
+func spread2(v uint64) uint64 {
+	v = (v | v<<16) & 0x0000ffff0000ffff
+	v = (v | v<<8) & 0x00ff00ff00ff00ff
+	v = (v | v<<4) & 0x0f0f0f0f0f0f0f0f
+	v = (v | v<<2) & 0x3333333333333333
+	v = (v | v<<1) & 0x5555555555555555
+	return v
+}
+
+// compact2 gathers the even bits of v into the low 32 bits; it is the
+// inverse of spread2.
+func compact2(v uint64) uint64 {
+	v &= 0x5555555555555555
+	v = (v | v>>1) & 0x3333333333333333
+	v = (v | v>>2) & 0x0f0f0f0f0f0f0f0f
+	v = (v | v>>4) & 0x00ff00ff00ff00ff
+	v = (v | v>>8) & 0x0000ffff0000ffff
+	v = (v | v>>16) & 0x00000000ffffffff
+	return v
+}
+
+// HilbertXY2D returns the distance along the Hilbert curve of order 64,
+// which fills the 2^64 × 2^64 grid, of the point (x, y). Points close
+// on the curve are close in the plane, giving better locality than
+// Morton codes at a slightly higher cost.
+func HilbertXY2D(x, y uint64) Uint128 {
+	var d Uint128
+	for i := 63; i >= 0; i-- {
+		s := uint64(1) << uint(i)
+		var rx, ry uint64
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		// d += s² * ((3*rx) ^ ry)
+		d = d.Add(Uint128{0, (3 * rx) ^ ry}.Lsh(uint(2 * i)))
+		x, y = hilbertRot(^uint64(0), x, y, rx, ry)
+	}
+	return d
+}
+
+// HilbertD2XY returns the point at distance d along the Hilbert curve
+// of order 64; it is the inverse of HilbertXY2D.
+func HilbertD2XY(d Uint128) (x, y uint64) {
+	for i := 0; i < 64; i++ {
+		s := uint64(1) << uint(i)
+		rx := 1 & (d.lo >> 1)
+		ry := 1 & (d.lo ^ rx)
+		x, y = hilbertRot(s-1, x, y, rx, ry)
+		x += s * rx
+		y += s * ry
+		d = d.Rsh(2)
+	}
+	return x, y
+}
+
+// hilbertRot rotates and flips the quadrant of (x, y) within the
+// square [0, m]² as required by the Hilbert curve recursion.
+func hilbertRot(m, x, y, rx, ry uint64) (uint64, uint64) {
+	if ry == 0 {
+		if rx == 1 {
+			x, y = m-x, m-y
+		}
+		x, y = y, x
+	}
+	return x, y
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		x, y uint64
+		want uint128
+	}{
+		{0, 0, uint128{0, 0}},
+		{1, 0, uint128{0, 1}},
+		{0, 1, uint128{0, 2}},
+		{3, 0, uint128{0, 5}},
+		{^uint64(0), 0, uint128{0x5555555555555555, 0x5555555555555555}},
+		{0, ^uint64(0), uint128{0xaaaaaaaaaaaaaaaa, 0xaaaaaaaaaaaaaaaa}},
+		{1 << 63, 1 << 63, uint128{3 << 62, 0}},
+		{1 << 32, 0, uint128{1, 0}},
+	}
+	for _, tt := range tests {
+		got := Interleave(tt.x, tt.y)
+		if got != tt.want {
+			t.Errorf("Interleave(%#x, %#x) = %#x; want %#x", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := rnd.Uint64(), rnd.Uint64()
+		m := Interleave(x, y)
+		for b := uint8(0); b < 64; b++ {
+			if m.BitLSB(2*b) != uint(x>>b&1) || m.BitLSB(2*b+1) != uint(y>>b&1) {
+				t.Fatalf("Interleave(%#x, %#x) = %#x; bit %d misplaced", x, y, m, b)
+			}
+		}
+		if gx, gy := m.Deinterleave(); gx != x || gy != y {
+			t.Errorf("%#x.Deinterleave() = %#x, %#x; want %#x, %#x", m, gx, gy, x, y)
+		}
+	}
+}
+
+func TestInterleave3(t *testing.T) {
+	const mask42 = 1<<42 - 1
+	tests := []struct {
+		x, y, z uint64
+		want    uint128
+	}{
+		{0, 0, 0, uint128{0, 0}},
+		{1, 0, 0, uint128{0, 1}},
+		{0, 1, 0, uint128{0, 2}},
+		{0, 0, 1, uint128{0, 4}},
+		{3, 0, 0, uint128{0, 9}},
+		{mask42, mask42, mask42, uint128{1<<62 - 1, ^uint64(0)}},
+		{^uint64(0), 0, 0, mortonMask3},
+	}
+	for _, tt := range tests {
+		if got := Interleave3(tt.x, tt.y, tt.z); got != tt.want {
+			t.Errorf("Interleave3(%#x, %#x, %#x) = %#x; want %#x", tt.x, tt.y, tt.z, got, tt.want)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, z := rnd.Uint64()&mask42, rnd.Uint64()&mask42, rnd.Uint64()&mask42
+		m := Interleave3(x, y, z)
+		for b := uint8(0); b < 42; b++ {
+			if m.BitLSB(3*b) != uint(x>>b&1) || m.BitLSB(3*b+1) != uint(y>>b&1) || m.BitLSB(3*b+2) != uint(z>>b&1) {
+				t.Fatalf("Interleave3(%#x, %#x, %#x) = %#x; bit %d misplaced", x, y, z, m, b)
+			}
+		}
+		if gx, gy, gz := m.Deinterleave3(); gx != x || gy != y || gz != z {
+			t.Errorf("%#x.Deinterleave3() = %#x, %#x, %#x; want %#x, %#x, %#x", m, gx, gy, gz, x, y, z)
+		}
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	tests := []struct {
+		x, y uint64
+		d    uint128
+	}{
+		{0, 0, uint128{0, 0}},
+		// The curve traverses the first 2×2 cell before moving on and,
+		// being of even order, ends at the far corner of the x axis.
+		{1, 0, uint128{0, 1}},
+		{1, 1, uint128{0, 2}},
+		{0, 1, uint128{0, 3}},
+		{0, 2, uint128{0, 4}},
+		{^uint64(0), 0, uint128{^uint64(0), ^uint64(0)}},
+	}
+	for _, tt := range tests {
+		if got := HilbertXY2D(tt.x, tt.y); got != tt.d {
+			t.Errorf("HilbertXY2D(%#x, %#x) = %#x; want %#x", tt.x, tt.y, got, tt.d)
+		}
+		if x, y := HilbertD2XY(tt.d); x != tt.x || y != tt.y {
+			t.Errorf("HilbertD2XY(%#x) = %#x, %#x; want %#x, %#x", tt.d, x, y, tt.x, tt.y)
+		}
+	}
+
+	// Consecutive distances map to neighbouring cells.
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		d := randUint128(rnd)
+		if d == (uint128{^uint64(0), ^uint64(0)}) {
+			continue
+		}
+		x0, y0 := HilbertD2XY(d)
+		x1, y1 := HilbertD2XY(d.AddOne())
+		dx, dy := x0-x1, y0-y1
+		if x1 > x0 {
+			dx = x1 - x0
+		}
+		if y1 > y0 {
+			dy = y1 - y0
+		}
+		if dx+dy != 1 {
+			t.Errorf("HilbertD2XY(%#x) = (%#x, %#x) and next = (%#x, %#x); not adjacent", d, x0, y0, x1, y1)
+		}
+		if got := HilbertXY2D(x0, y0); got != d {
+			t.Errorf("HilbertXY2D(HilbertD2XY(%#x)) = %#x", d, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "errors"
+
+// MessagePack ext format headers carrying a 16-byte payload.
+const (
+	msgpackFixExt16 = 0xd8
+	msgpackExt8     = 0xc7
+)
+
+// MsgpackExtType is the MessagePack extension type code used for
+// Uint128 values. Application-defined codes range from 0 to 127; change
+// it if 16 collides with another extension, but only during
+// initialization, before any concurrent encoding or decoding.
+var MsgpackExtType int8 = 16
+
+// AppendMsgpack appends the MessagePack encoding of u to dst and
+// returns the extended buffer. The encoding is a fixext 16 item of
+// type MsgpackExtType whose payload is the 16-byte big-endian encoding
+// returned by Bytes.
+func (u Uint128) AppendMsgpack(dst []byte) []byte {
+	dst = append(dst, msgpackFixExt16, byte(MsgpackExtType))
+	return u.AppendBytes(dst)
+}
+
+// DecodeMsgpack decodes a MessagePack ext item of type MsgpackExtType
+// with a 16-byte payload from the start of b, as written by
+// AppendMsgpack, and returns it together with the number of bytes
+// read. Both the fixext 16 and the ext 8 header are accepted.
+func DecodeMsgpack(b []byte) (u Uint128, n int, err error) {
+	switch {
+	case len(b) >= 2 && b[0] == msgpackFixExt16:
+		n = 2
+	case len(b) >= 3 && b[0] == msgpackExt8 && b[1] == 16:
+		n = 3
+	default:
+		return Uint128{}, 0, errors.New("uint128: DecodeMsgpack: not a 16-byte ext item")
+	}
+	if int8(b[n-1]) != MsgpackExtType {
+		return Uint128{}, 0, errors.New("uint128: DecodeMsgpack: unexpected ext type")
+	}
+	if len(b) < n+16 {
+		return Uint128{}, 0, errors.New("uint128: DecodeMsgpack: short payload")
+	}
+	return FromBytesBE([16]byte(b[n:])), n + 16, nil
+}
+
+// MarshalMsgpack implements the msgpack.Marshaler interface of
+// github.com/vmihailenco/msgpack. It returns the complete ext item
+// written by AppendMsgpack, so the type must not also be registered
+// with msgpack.RegisterExt, which would wrap it a second time.
+func (u Uint128) MarshalMsgpack() ([]byte, error) {
+	return u.AppendMsgpack(make([]byte, 0, 18)), nil
+}
+
+// UnmarshalMsgpack implements the msgpack.Unmarshaler interface of
+// github.com/vmihailenco/msgpack. The data must hold exactly one item
+// accepted by DecodeMsgpack.
+func (u *Uint128) UnmarshalMsgpack(data []byte) error {
+	v, n, err := DecodeMsgpack(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return errors.New("uint128: UnmarshalMsgpack: trailing data")
+	}
+	*u = v
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMsgpack(t *testing.T) {
+	u := uint128{0x0102030405060708, 0x090a0b0c0d0e0f10}
+	const want = "d810" + "0102030405060708090a0b0c0d0e0f10"
+	if got := hex.EncodeToString(u.AppendMsgpack(nil)); got != want {
+		t.Errorf("AppendMsgpack = %s; want %s", got, want)
+	}
+
+	tests := []struct {
+		in   string
+		n    int
+		want uint128
+		ok   bool
+	}{
+		{want, 18, u, true},
+		{want + "c0", 18, u, true},
+		{"c71010" + "0102030405060708090a0b0c0d0e0f10", 19, u, true},
+		{"", 0, uint128{}, false},
+		{"d811" + "0102030405060708090a0b0c0d0e0f10", 0, uint128{}, false}, // ext type
+		{"d810" + "01020304", 0, uint128{}, false},                         // short
+		{"c70810" + "0102030405060708", 0, uint128{}, false},               // ext 8, length 8
+		{"d710" + "0102030405060708", 0, uint128{}, false},                 // fixext 8
+		{"cf0102030405060708", 0, uint128{}, false},                        // uint 64
+	}
+	for _, tt := range tests {
+		b, _ := hex.DecodeString(tt.in)
+		got, n, err := DecodeMsgpack(b)
+		if (err == nil) != tt.ok || got != tt.want || n != tt.n {
+			t.Errorf("DecodeMsgpack(%s) = %v, %d, %v; want %v, %d, ok %v", tt.in, got, n, err, tt.want, tt.n, tt.ok)
+		}
+	}
+
+	var v uint128
+	if err := v.UnmarshalMsgpack(append(u.AppendMsgpack(nil), 0xc0)); err == nil {
+		t.Error("UnmarshalMsgpack accepted trailing data")
+	}
+}
+
+func TestMsgpackLibrary(t *testing.T) {
+	type record struct {
+		ID   Uint128
+		Ptr  *Uint128
+		List []Uint128
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		u := randUint128(r)
+		in := record{u, &u, []Uint128{u, Max, Zero}}
+		b, err := msgpack.Marshal(&in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(b, u.AppendMsgpack(nil)) {
+			t.Fatalf("msgpack.Marshal(%v) = %x; missing ext item", u, b)
+		}
+		var out record
+		if err := msgpack.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.ID != u || out.Ptr == nil || *out.Ptr != u || len(out.List) != 3 || out.List[0] != u || out.List[1] != Max || out.List[2] != Zero {
+			t.Fatalf("msgpack round trip of %v = %+v", u, out)
+		}
+	}
+}
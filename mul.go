@@ -0,0 +1,40 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "math/bits"
+
+// Mul returns the low 128 bits of the product u * m,
+// wrapping around on overflow.
+func (u Uint128) Mul(m Uint128) Uint128 {
+	hi, lo := bits.Mul64(u.lo, m.lo)
+	hi += u.hi*m.lo + u.lo*m.hi
+	return Uint128{hi, lo}
+}
+
+// MulFull returns the full 256-bit product of u and m,
+// with the upper half returned in hi and the lower half in lo.
+func (u Uint128) MulFull(m Uint128) (hi, lo Uint128) {
+	h00, l00 := bits.Mul64(u.lo, m.lo)
+	h01, l01 := bits.Mul64(u.lo, m.hi)
+	h10, l10 := bits.Mul64(u.hi, m.lo)
+	h11, l11 := bits.Mul64(u.hi, m.hi)
+
+	r1, c1 := bits.Add64(h00, l01, 0)
+	r1, c2 := bits.Add64(r1, l10, 0)
+	r2, c3 := bits.Add64(h01, h10, c1)
+	r2, c4 := bits.Add64(r2, l11, c2)
+	r3 := h11 + c3 + c4
+	return Uint128{r3, r2}, Uint128{r1, l00}
+}
+
+// Mul64 returns the low 128 bits of the product u * m,
+// wrapping around on overflow.
+//
+// It is cheaper than Mul when the multiplier fits in 64 bits.
+func (u Uint128) Mul64(m uint64) Uint128 {
+	hi, lo := bits.Mul64(u.lo, m)
+	return Uint128{hi + u.hi*m, lo}
+}
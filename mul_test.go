@@ -0,0 +1,66 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"testing"
+)
+
+// mulTests are operand pairs shared by the multiplication tests.
+var mulTests = []struct {
+	a, b uint128
+}{
+	{uint128{0, 0}, uint128{0, 0}},
+	{uint128{0, 1}, uint128{0, 1}},
+	{uint128{0, 7}, uint128{0, 6}},
+	{uint128{0, ^uint64(0)}, uint128{0, ^uint64(0)}},
+	{uint128{0, ^uint64(0)}, uint128{0, 2}},
+	{uint128{1, 0}, uint128{1, 0}},
+	{uint128{1, 0}, uint128{0, 3}},
+	{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x0f1e2d3c4b5a6978, 0x8796a5b4c3d2e1f0}},
+	{uint128{^uint64(0), ^uint64(0)}, uint128{^uint64(0), ^uint64(0)}},
+	{uint128{^uint64(0), ^uint64(0)}, uint128{0, 2}},
+	{uint128{1 << 63, 0}, uint128{0, 2}},
+}
+
+func TestMul(t *testing.T) {
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, tt := range mulTests {
+		got := tt.a.Mul(tt.b)
+		want := new(big.Int).Mul(toBig(tt.a), toBig(tt.b))
+		want.Mod(want, mod)
+		if toBig(got).Cmp(want) != 0 {
+			t.Errorf("%v.Mul(%v) = %v; want %v", tt.a, tt.b, got, fromBig(want))
+		}
+		if got2 := tt.b.Mul(tt.a); got2 != got {
+			t.Errorf("%v.Mul(%v) = %v; not commutative with %v", tt.b, tt.a, got2, got)
+		}
+	}
+}
+
+func TestMulFull(t *testing.T) {
+	for _, tt := range mulTests {
+		hi, lo := tt.a.MulFull(tt.b)
+		got := new(big.Int).Lsh(toBig(hi), 128)
+		got.Or(got, toBig(lo))
+		want := new(big.Int).Mul(toBig(tt.a), toBig(tt.b))
+		if got.Cmp(want) != 0 {
+			t.Errorf("%v.MulFull(%v) = %v, %v; want %x", tt.a, tt.b, hi, lo, want)
+		}
+		if trunc := tt.a.Mul(tt.b); lo != trunc {
+			t.Errorf("%v.MulFull(%v) low half = %v; Mul gives %v", tt.a, tt.b, lo, trunc)
+		}
+	}
+}
+
+func TestMul64(t *testing.T) {
+	for _, tt := range mulTests {
+		got := tt.a.Mul64(tt.b.lo)
+		if want := tt.a.Mul(uint128{0, tt.b.lo}); got != want {
+			t.Errorf("%v.Mul64(%#x) = %v; want %v", tt.a, tt.b.lo, got, want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// GCD returns the greatest common divisor of u and v, computed with
+// the binary (Stein) algorithm. GCD(0, v) == v, so GCD(0, 0) == 0.
+func (u Uint128) GCD(v Uint128) Uint128 {
+	if u.IsZero() {
+		return v
+	}
+	if v.IsZero() {
+		return u
+	}
+	// Factor out the powers of two common to u and v, then repeatedly
+	// replace the larger of the (now odd) values by their difference
+	// with its powers of two removed.
+	shift := uint(u.Or(v).TrailingZeros())
+	u = u.Rsh(uint(u.TrailingZeros()))
+	for {
+		v = v.Rsh(uint(v.TrailingZeros()))
+		if u.Cmp(v) > 0 {
+			u, v = v, u
+		}
+		v = v.Sub(u)
+		if v.IsZero() {
+			return u.Lsh(shift)
+		}
+	}
+}
+
+// LCM returns the least common multiple of u and v and whether it fits
+// in 128 bits. On overflow the result modulo 2^128 is returned with
+// ok == false. LCM(0, v) == 0.
+func (u Uint128) LCM(v Uint128) (lcm Uint128, ok bool) {
+	if u.IsZero() || v.IsZero() {
+		return Uint128{}, true
+	}
+	return u.Div(u.GCD(v)).MulChecked(v)
+}
+
+// ModAdd returns (u + v) mod m. The operands need not be reduced.
+// Like the other Mod methods, it panics if m is zero.
+func (u Uint128) ModAdd(v, m Uint128) Uint128 {
+	u, v = u.reduce(m), v.reduce(m)
+	s, carry := u.AddCarry(v, 0)
+	if carry != 0 || s.Cmp(m) >= 0 {
+		s = s.Sub(m)
+	}
+	return s
+}
+
+// ModSub returns (u - v) mod m, always in the range [0, m).
+// The operands need not be reduced.
+func (u Uint128) ModSub(v, m Uint128) Uint128 {
+	u, v = u.reduce(m), v.reduce(m)
+	d, borrow := u.SubBorrow(v, 0)
+	if borrow != 0 {
+		d = d.Add(m)
+	}
+	return d
+}
+
+// ModMul returns (u * v) mod m, using the full 256-bit product.
+// The operands need not be reduced.
+func (u Uint128) ModMul(v, m Uint128) Uint128 {
+	return NewDivisor(m).mulMod(u, v)
+}
+
+// ModExp returns u**e mod m, computed by square-and-multiply with
+// 256-bit intermediate products. ModExp(0, m) is 1 mod m.
+func (u Uint128) ModExp(e, m Uint128) Uint128 {
+	d := NewDivisor(m)
+	r, b := Uint128{0, 1}.reduce(m), u.reduce(m)
+	for !e.IsZero() {
+		if e.lo&1 != 0 {
+			r = d.mulMod(r, b)
+		}
+		e = e.Rsh(1)
+		if !e.IsZero() {
+			b = d.mulMod(b, b)
+		}
+	}
+	return r
+}
+
+// reduce returns u mod m, skipping the division if u is already
+// reduced. It panics if m is zero.
+func (u Uint128) reduce(m Uint128) Uint128 {
+	if u.Cmp(m) < 0 {
+		return u
+	}
+	return u.Mod(m)
+}
+
+// ModInverse returns the multiplicative inverse of u modulo m, the x
+// in [0, m) with u*x == 1 mod m, computed with the extended Euclidean
+// algorithm. If gcd(u, m) != 1 there is no inverse and ok is false.
+// It panics if m is zero.
+func (u Uint128) ModInverse(m Uint128) (inv Uint128, ok bool) {
+	// The Bézout coefficients t_k of u alternate in sign and are
+	// bounded by m, so track their magnitudes, which then obey
+	// |t_k+1| = |t_k-1| + q*|t_k|, and recover the sign from the
+	// parity of k at the end.
+	r0, r1 := m, u.reduce(m)
+	t0, t1 := Uint128{}, Uint128{0, 1}
+	k := 1
+	for !r1.IsZero() {
+		q, r := r0.QuoRem(r1)
+		r0, r1 = r1, r
+		t0, t1 = t1, t0.Add(q.Mul(t1))
+		k++
+	}
+	if r0 != (Uint128{0, 1}) {
+		return Uint128{}, false
+	}
+	if (k-1)%2 == 0 && !t0.IsZero() {
+		t0 = m.Sub(t0)
+	}
+	return t0, true
+}
+
+// Jacobi returns the Jacobi symbol (a/n), either +1, -1, or 0.
+// The n argument must be odd; Jacobi panics otherwise.
+func Jacobi(a, n Uint128) int {
+	if n.lo&1 == 0 {
+		panic("uint128: Jacobi symbol with even modulus")
+	}
+	a = a.Mod(n)
+	j := 1
+	for !a.IsZero() {
+		// (2/n) = -1 exactly when n = 3 or 5 mod 8.
+		t := uint(a.TrailingZeros())
+		a = a.Rsh(t)
+		if r := n.lo & 7; t&1 != 0 && (r == 3 || r == 5) {
+			j = -j
+		}
+		// Quadratic reciprocity.
+		a, n = n, a
+		if a.lo&3 == 3 && n.lo&3 == 3 {
+			j = -j
+		}
+		a = a.Mod(n)
+	}
+	if n != (Uint128{0, 1}) {
+		return 0
+	}
+	return j
+}
+
+// SqrtMod returns a square root x of u modulo the prime p, such that
+// x*x == u mod p, computed with the Tonelli-Shanks algorithm. The other
+// root is p - x. If u is not a quadratic residue modulo p, ok is false.
+//
+// The result is meaningless if p is not prime.
+func (u Uint128) SqrtMod(p Uint128) (x Uint128, ok bool) {
+	a := u.reduce(p)
+	if a.IsZero() || p == (Uint128{0, 2}) {
+		return a, true
+	}
+	if p.lo&1 == 0 || Jacobi(a, p) != 1 {
+		return Uint128{}, false
+	}
+	mt := NewMontgomery(p)
+	am := mt.ToMont(a)
+	if p.lo&3 == 3 {
+		// x = a^((p+1)/4), where (p+1)/4 == p>>2 + 1.
+		x = mt.FromMont(mt.ExpMont(am, p.Rsh(2).AddOne()))
+		return x, mt.MulMont(mt.ToMont(x), mt.ToMont(x)) == am
+	}
+
+	// p-1 == q*2^s with q odd; find a non-residue z.
+	pm1 := p.SubOne()
+	s := uint(pm1.TrailingZeros())
+	q := pm1.Rsh(s)
+	z := Uint128{0, 2}
+	for Jacobi(z, p) != -1 {
+		z = z.AddOne()
+	}
+	m := s
+	c := mt.ExpMont(mt.ToMont(z), q)
+	t := mt.ExpMont(am, q)
+	r := mt.ExpMont(am, q.Rsh(1).AddOne())
+	for t != mt.one {
+		// Find the least i with t^(2^i) == 1.
+		i, t2 := uint(0), t
+		for t2 != mt.one {
+			t2 = mt.MulMont(t2, t2)
+			if i++; i == m {
+				return Uint128{}, false // p is not prime
+			}
+		}
+		b := c
+		for j := uint(0); j < m-i-1; j++ {
+			b = mt.MulMont(b, b)
+		}
+		m = i
+		c = mt.MulMont(b, b)
+		t = mt.MulMont(t, c)
+		r = mt.MulMont(r, b)
+	}
+	return mt.FromMont(r), true
+}
+
+// CRT combines the congruences x == a mod m and x == b mod n using the
+// Chinese Remainder Theorem, returning the unique x in [0, lcm(m, n))
+// satisfying both. The moduli need not be coprime. If the congruences
+// are incompatible, or lcm(m, n) does not fit in 128 bits, ok is false.
+// CRT panics if m or n is zero.
+func CRT(a, m, b, n Uint128) (x Uint128, ok bool) {
+	a = a.reduce(m)
+	g := m.GCD(n)
+	if _, ok := m.LCM(n); !ok {
+		return Uint128{}, false
+	}
+	// b - a (mod n) is divisible by g exactly when the congruences are
+	// compatible, since g divides n.
+	d, r := b.ModSub(a, n).QuoRem(g)
+	if !r.IsZero() {
+		return Uint128{}, false
+	}
+	// x = a + m*k with m*k == b - a (mod n), i.e.
+	// k == (b-a)/g * (m/g)^-1 (mod n/g). The sum stays below
+	// m + m*(n/g - 1) == lcm(m, n).
+	ng := n.Div(g)
+	inv, _ := m.Div(g).ModInverse(ng)
+	k := d.ModMul(inv, ng)
+	return a.Add(m.Mul(k)), true
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestGCDLCM(t *testing.T) {
+	tests := []struct {
+		a, b uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 0}, uint128{0, 12}},
+		{uint128{0, 12}, uint128{0, 0}},
+		{uint128{0, 12}, uint128{0, 18}},
+		{uint128{0, 17}, uint128{0, 19}},
+		{uint128{1, 0}, uint128{0, 1 << 40}},
+		{uint128{1 << 63, 0}, uint128{1 << 63, 0}},
+		{uint128{^uint64(0), ^uint64(0)}, uint128{0, 3}},
+		{uint128{^uint64(0), ^uint64(0)}, uint128{^uint64(0), ^uint64(0) - 1}},
+		{uint128{0x0123456789abcdef, 0xfedcba9876543210}, uint128{0x1, 0x8796a5b4c3d2e1f0}},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		g := randUint128(rnd).Rsh(64)
+		a, b := randUint128(rnd).Rsh(64), randUint128(rnd).Rsh(64)
+		tests = append(tests, struct{ a, b uint128 }{a.Mul(g), b.Mul(g)})
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, tt := range tests {
+		want := new(big.Int).GCD(nil, nil, toBig(tt.a), toBig(tt.b))
+		if tt.a.IsZero() || tt.b.IsZero() {
+			// math/big defines GCD(0, b) == |b| as well.
+			want = new(big.Int).Add(toBig(tt.a), toBig(tt.b))
+		}
+		got := tt.a.GCD(tt.b)
+		if toBig(got).Cmp(want) != 0 {
+			t.Errorf("%v.GCD(%v) = %v; want %v", tt.a, tt.b, got, fromBig(want))
+		}
+
+		wantLCM := new(big.Int)
+		if !tt.a.IsZero() && !tt.b.IsZero() {
+			wantLCM.Mul(toBig(tt.a), toBig(tt.b))
+			wantLCM.Quo(wantLCM, want)
+		}
+		wantOK := wantLCM.Cmp(limit) < 0
+		lcm, ok := tt.a.LCM(tt.b)
+		if lcm != fromBig(wantLCM) || ok != wantOK {
+			t.Errorf("%v.LCM(%v) = %v, %v; want %v, %v", tt.a, tt.b, lcm, ok, fromBig(wantLCM), wantOK)
+		}
+	}
+}
+
+func TestModArith(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	moduli := []uint128{
+		{0, 1}, {0, 2}, {0, 7}, {0, 1000000007}, {0, ^uint64(0)}, {1, 0}, {1, 1},
+		{0xfffffffffffffffe, 0xffffffffffffffff}, {^uint64(0), ^uint64(0) - 158}, max,
+	}
+	operands := []uint128{
+		{0, 0}, {0, 1}, {0, 6}, {0, ^uint64(0)}, {1, 0},
+		{0x0123456789abcdef, 0xfedcba9876543210}, {^uint64(0), ^uint64(0) - 1}, max,
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		if m := randUint128(rnd); !m.IsZero() {
+			moduli = append(moduli, m)
+		}
+		operands = append(operands, randUint128(rnd))
+	}
+	for _, m := range moduli {
+		bm := toBig(m)
+		for _, a := range operands {
+			for _, b := range operands {
+				ba, bb := toBig(a), toBig(b)
+				want := new(big.Int).Add(ba, bb)
+				if got := a.ModAdd(b, m); toBig(got).Cmp(want.Mod(want, bm)) != 0 {
+					t.Errorf("%v.ModAdd(%v, %v) = %v; want %v", a, b, m, got, fromBig(want))
+				}
+				want = new(big.Int).Sub(ba, bb)
+				if got := a.ModSub(b, m); toBig(got).Cmp(want.Mod(want, bm)) != 0 {
+					t.Errorf("%v.ModSub(%v, %v) = %v; want %v", a, b, m, got, fromBig(want))
+				}
+				want = new(big.Int).Mul(ba, bb)
+				if got := a.ModMul(b, m); toBig(got).Cmp(want.Mod(want, bm)) != 0 {
+					t.Errorf("%v.ModMul(%v, %v) = %v; want %v", a, b, m, got, fromBig(want))
+				}
+				want = new(big.Int).Exp(ba, bb, bm)
+				if got := a.ModExp(b, m); toBig(got).Cmp(want) != 0 {
+					t.Errorf("%v.ModExp(%v, %v) = %v; want %v", a, b, m, got, fromBig(want))
+				}
+			}
+		}
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	moduli := []uint128{
+		{0, 1}, {0, 2}, {0, 7}, {0, 12}, {0, 1000000007}, {1, 0},
+		{^uint64(0), ^uint64(0) - 158}, {^uint64(0), ^uint64(0)},
+	}
+	operands := []uint128{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 5}, {^uint64(0), ^uint64(0)}}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 30; i++ {
+		if m := randUint128(rnd); !m.IsZero() {
+			moduli = append(moduli, m)
+		}
+		operands = append(operands, randUint128(rnd))
+	}
+	for _, m := range moduli {
+		for _, a := range operands {
+			want := new(big.Int).ModInverse(toBig(a), toBig(m))
+			inv, ok := a.ModInverse(m)
+			if m == (uint128{0, 1}) {
+				// Everything is congruent to 0 modulo 1, including 1 itself.
+				if !ok || !inv.IsZero() {
+					t.Errorf("%v.ModInverse(1) = %v, %v; want 0, true", a, inv, ok)
+				}
+				continue
+			}
+			if want == nil {
+				if ok {
+					t.Errorf("%v.ModInverse(%v) = %v, true; want false", a, m, inv)
+				}
+				continue
+			}
+			if !ok || toBig(inv).Cmp(want) != 0 {
+				t.Errorf("%v.ModInverse(%v) = %v, %v; want %v, true", a, m, inv, ok, fromBig(want))
+			}
+		}
+	}
+}
+
+func TestJacobi(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, n := randUint128(rnd), randUint128(rnd).Or(uint128{0, 1})
+		if got, want := Jacobi(a, n), big.Jacobi(toBig(a), toBig(n)); got != want {
+			t.Errorf("Jacobi(%v, %v) = %d; want %d", a, n, got, want)
+		}
+	}
+}
+
+func TestSqrtMod(t *testing.T) {
+	primes := []uint128{
+		{0, 2}, {0, 3}, {0, 5}, {0, 13}, {0, 17}, {0, 97}, {0, 1000000007},
+		{0, 998244353},            // 119·2^23 + 1, so s = 23
+		{0, 18446744069414584321}, // 2^64 - 2^32 + 1, s = 32
+		{0, 18446744073709551557}, {^uint64(0), ^uint64(0) - 158},
+		{0x7fffffffffffffff, 0xffffffffffffffff}, // 2^127 - 1
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, p := range primes {
+		for i := 0; i < 50; i++ {
+			a := randUint128(rnd)
+			x, ok := a.SqrtMod(p)
+			wantOK := p.lo == 2 || a.Mod(p).IsZero() || Jacobi(a, p) == 1
+			if ok != wantOK {
+				t.Errorf("%v.SqrtMod(%v) ok = %v; want %v", a, p, ok, wantOK)
+				continue
+			}
+			if ok && x.ModMul(x, p) != a.Mod(p) {
+				t.Errorf("%v.SqrtMod(%v) = %v; %v² mod p = %v", a, p, x, x, x.ModMul(x, p))
+			}
+			// Every square has a root.
+			sq := a.ModMul(a, p)
+			if x, ok := sq.SqrtMod(p); !ok || x.ModMul(x, p) != sq {
+				t.Errorf("%v.SqrtMod(%v) = %v, %v; want a root", sq, p, x, ok)
+			}
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, m, b, n uint128
+		want       uint128
+		ok         bool
+	}{
+		{uint128{0, 2}, uint128{0, 3}, uint128{0, 3}, uint128{0, 5}, uint128{0, 8}, true},
+		{uint128{0, 0}, uint128{0, 1}, uint128{0, 0}, uint128{0, 1}, uint128{0, 0}, true},
+		{uint128{0, 5}, uint128{0, 3}, uint128{0, 3}, uint128{0, 5}, uint128{0, 8}, true}, // a not reduced
+		{uint128{0, 1}, uint128{0, 4}, uint128{0, 3}, uint128{0, 6}, uint128{0, 9}, true}, // non-coprime
+		{uint128{0, 1}, uint128{0, 4}, uint128{0, 2}, uint128{0, 6}, uint128{}, false},    // incompatible
+		{uint128{0, 1}, uint128{1, 0}, uint128{0, 1}, uint128{1, 1}, uint128{}, false},    // lcm overflows
+		{uint128{0, 7}, max, uint128{0, 7}, max, uint128{0, 7}, true},
+		{uint128{0, 3}, uint128{0, 1 << 63}, uint128{0, 4}, uint128{0, 3}, uint128{1, 3}, true}, // 2^64 + 3
+	}
+	for _, tt := range tests {
+		got, ok := CRT(tt.a, tt.m, tt.b, tt.n)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CRT(%v, %v, %v, %v) = %v, %v; want %v, %v", tt.a, tt.m, tt.b, tt.n, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		m, n := randUint128(rnd).Rsh(64).AddOne(), randUint128(rnd).Rsh(64).AddOne()
+		x := randUint128(rnd)
+		l, _ := m.LCM(n)
+		got, ok := CRT(x.Mod(m), m, x.Mod(n), n)
+		if !ok || got != x.Mod(l) {
+			t.Errorf("CRT(%v, %v, %v, %v) = %v, %v; want %v, true", x.Mod(m), m, x.Mod(n), n, got, ok, x.Mod(l))
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MustParse returns the Uint128 represented by s, which is written
+// like a Go integer literal: decimal, or with a 0b, 0o or 0x base
+// prefix, optionally with underscores between digits (see
+// ParseUint128 with base 0). Unlike in Go, a leading 0 alone is not an
+// octal prefix, so "00042" is 42, as with UnmarshalText. It panics if
+// s is malformed or does not fit in 128 bits. It is intended for
+// initializing package-level variables from constant strings.
+func MustParse(s string) Uint128 {
+	u, err := parseUint128(s, 0, false)
+	if err != nil {
+		panic("uint128: " + err.Error())
+	}
+	return u
+}
+
+// ParseUint128 is like strconv.ParseUint for 128-bit values: it
+// interprets s in the given base (0, or 2 to MaxBase) and returns the
+// corresponding value. A sign prefix is not permitted. For bases up
+// to 36, letters are case-insensitive; for larger bases, 'a' to 'z'
+// stand for 10 to 35 and 'A' to 'Z' for 36 to 61, matching Text.
+//
+// If base is 0, the base is implied by the string's prefix: "0b" for
+// base 2, "0o" or "0" for base 8, "0x" for base 16, and base 10
+// otherwise. Also, for base 0 only, underscore characters are
+// permitted as defined by the Go syntax for integer literals.
+//
+// Errors are of type *strconv.NumError with Func "ParseUint128". If s
+// is empty or contains invalid digits, err.Err is strconv.ErrSyntax
+// and the returned value is 0; if the value does not fit in 128 bits,
+// err.Err is strconv.ErrRange and the returned value is Max.
+func ParseUint128(s string, base int) (Uint128, error) {
+	return parseUint128(s, base, true)
+}
+
+// parseUint128 implements ParseUint128. If octalZero is false, a
+// leading 0 without a letter does not select base 8 when base is 0.
+func parseUint128(s string, base int, octalZero bool) (Uint128, error) {
+	const fnParse = "ParseUint128"
+
+	if s == "" {
+		return Uint128{}, syntaxError(fnParse, s)
+	}
+	s0 := s
+	base0 := base == 0
+	switch {
+	case 2 <= base && base <= MaxBase:
+		// valid base; nothing to do
+	case base == 0:
+		base = 10
+		if s[0] == '0' {
+			switch {
+			case len(s) >= 3 && lower(s[1]) == 'b':
+				base, s = 2, s[2:]
+			case len(s) >= 3 && lower(s[1]) == 'o':
+				base, s = 8, s[2:]
+			case len(s) >= 3 && lower(s[1]) == 'x':
+				base, s = 16, s[2:]
+			case octalZero:
+				base, s = 8, s[1:]
+			}
+		}
+	default:
+		return Uint128{}, &strconv.NumError{Func: fnParse, Num: s0, Err: errors.New("invalid base " + strconv.Itoa(base))}
+	}
+
+	var u Uint128
+	underscores := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' && base0 {
+			underscores = true
+			continue
+		}
+		d := digitVal(s[i], base)
+		if d >= base {
+			return Uint128{}, syntaxError(fnParse, s0)
+		}
+		var ok bool
+		if u, ok = u.Mul64Checked(uint64(base)); ok {
+			u, ok = u.AddChecked(Uint128{0, uint64(d)})
+		}
+		if !ok {
+			return Max, &strconv.NumError{Func: fnParse, Num: s0, Err: strconv.ErrRange}
+		}
+	}
+	if underscores && !underscoreOK(s0) {
+		return Uint128{}, syntaxError(fnParse, s0)
+	}
+	return u, nil
+}
+
+// ParseSortable parses the encoding produced by SortableString: exactly
+// SortableLen decimal digits. Errors are of type *strconv.NumError with
+// Func "ParseSortable".
+func ParseSortable(s string) (Uint128, error) {
+	const fnParseSortable = "ParseSortable"
+
+	if len(s) != SortableLen {
+		return Uint128{}, syntaxError(fnParseSortable, s)
+	}
+	u, err := ParseUint128(s, 10)
+	if err != nil {
+		err.(*strconv.NumError).Func = fnParseSortable
+	}
+	return u, err
+}
+
+// ParseBits parses a string of exactly 128 '0' and '1' characters,
+// most significant bit first, as produced by FormatBits. Underscores
+// and spaces are ignored, so the output of FormatBitsGrouped with
+// either separator is accepted too. Errors are of type
+// *strconv.NumError with Func "ParseBits".
+func ParseBits(s string) (Uint128, error) {
+	var u Uint128
+	n := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '_', ' ':
+			continue
+		case '0', '1':
+			if n == 128 {
+				return Uint128{}, syntaxError("ParseBits", s)
+			}
+			if c == '1' {
+				u = u.SetBit(uint8(n))
+			}
+			n++
+		default:
+			return Uint128{}, syntaxError("ParseBits", s)
+		}
+	}
+	if n != 128 {
+		return Uint128{}, syntaxError("ParseBits", s)
+	}
+	return u, nil
+}
+
+// underscoreOK reports whether the underscores in s are allowed.
+// Checking them in this one function lets all the parsers skip over
+// them simply. Underscores must appear only between digits or
+// between a base prefix and a digit.
+func underscoreOK(s string) bool {
+	// saw tracks the last character (class) we saw:
+	// ^ for beginning of number,
+	// 0 for a digit or base prefix,
+	// _ for an underscore,
+	// ! for none of the above.
+	saw := '^'
+	i := 0
+
+	// Optional base prefix.
+	hex := false
+	if len(s) >= 2 && s[0] == '0' && (lower(s[1]) == 'b' || lower(s[1]) == 'o' || lower(s[1]) == 'x') {
+		i = 2
+		saw = '0' // base prefix counts as a digit for "underscore as digit separator"
+		hex = lower(s[1]) == 'x'
+	}
+
+	// Number proper.
+	for ; i < len(s); i++ {
+		// Digits are always okay.
+		if '0' <= s[i] && s[i] <= '9' || hex && 'a' <= lower(s[i]) && lower(s[i]) <= 'f' {
+			saw = '0'
+			continue
+		}
+		// Underscore must follow digit.
+		if s[i] == '_' {
+			if saw != '0' {
+				return false
+			}
+			saw = '_'
+			continue
+		}
+		// Underscore must also be followed by digit.
+		if saw == '_' {
+			return false
+		}
+		// Saw non-digit, non-underscore.
+		saw = '!'
+	}
+	return saw != '_'
+}
+
+func syntaxError(fn, s string) *strconv.NumError {
+	return &strconv.NumError{Func: fn, Num: s, Err: strconv.ErrSyntax}
+}
+
+// lower returns the lowercase version of the ASCII letter c, and an
+// arbitrary value for other bytes.
+func lower(c byte) byte { return c | ('x' - 'X') }
+
+// digitVal returns the value of the digit c in base, or MaxBase if c
+// is not a digit or letter. Letters are case-insensitive for bases up
+// to 36.
+func digitVal(c byte, base int) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'z':
+		return int(c - 'a' + 10)
+	case 'A' <= c && c <= 'Z':
+		if base <= 36 {
+			return int(c - 'A' + 10)
+		}
+		return int(c - 'A' + 36)
+	}
+	return MaxBase
+}
+
+// Scan implements fmt.Scanner, so that a *Uint128 can be passed to
+// fmt.Sscan and friends. It accepts the verbs 'b' (binary), 'o'
+// (octal), 'd' (decimal), 'x' and 'X' (hexadecimal), and 's' and 'v',
+// which detect the base from a 0b, 0o, 0 or 0x prefix as
+// ParseUint128 does with base 0 and also accept underscores. As with
+// fmt's integer verbs, scanning stops at the first rune that is not a
+// digit in the base, which is left unread.
+func (u *Uint128) Scan(state fmt.ScanState, verb rune) error {
+	var base int
+	switch verb {
+	case 'b':
+		base = 2
+	case 'o':
+		base = 8
+	case 'd':
+		base = 10
+	case 'x', 'X':
+		base = 16
+	case 's', 'v':
+		base = 0
+	default:
+		return errors.New("uint128: invalid verb for Scan")
+	}
+	state.SkipSpace()
+	var tok []byte
+	if base == 0 {
+		// Like fmt's own integer scanning, read a base prefix and then
+		// only the digits of that base, plus underscores.
+		base = 10
+		if tok = scanByte(state, tok, func(c byte) bool { return c == '0' }); len(tok) == 1 {
+			base = 8
+			tok = scanByte(state, tok, func(c byte) bool {
+				switch lower(c) {
+				case 'b':
+					base = 2
+				case 'o':
+					base = 8
+				case 'x':
+					base = 16
+				default:
+					return false
+				}
+				return true
+			})
+		}
+		tok = scanDigits(state, tok, base, true)
+		base = 0
+	} else {
+		tok = scanDigits(state, tok, base, false)
+	}
+	if len(tok) == 0 {
+		if _, _, err := state.ReadRune(); err == io.EOF {
+			return io.EOF
+		}
+		state.UnreadRune()
+	}
+	v, err := ParseUint128(string(tok), base)
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// scanByte reads the next rune from state and appends it to buf if it
+// is an ASCII byte satisfying ok. Otherwise it leaves the rune unread.
+func scanByte(state fmt.ScanState, buf []byte, ok func(byte) bool) []byte {
+	r, _, err := state.ReadRune()
+	if err != nil {
+		return buf
+	}
+	if r < utf8.RuneSelf && ok(byte(r)) {
+		return append(buf, byte(r))
+	}
+	state.UnreadRune()
+	return buf
+}
+
+// scanDigits appends to buf the longest run of digits in base read
+// from state, also accepting underscores if underscores is set.
+func scanDigits(state fmt.ScanState, buf []byte, base int, underscores bool) []byte {
+	for {
+		n := len(buf)
+		buf = scanByte(state, buf, func(c byte) bool {
+			return digitVal(c, base) < base || underscores && c == '_'
+		})
+		if len(buf) == n {
+			return buf
+		}
+	}
+}
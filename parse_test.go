@@ -0,0 +1,220 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMustParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want uint128
+	}{
+		{"0", uint128{}},
+		{"00042", uint128{0, 42}},
+		{"0_99", uint128{0, 99}},
+		{"0o52", uint128{0, 42}},
+		{"0b101010", uint128{0, 42}},
+		{"0x_dead_beef", uint128{0, 0xdeadbeef}},
+		{"1_000_000", uint128{0, 1e6}},
+		{"0xffff_ffff_ffff_ffff_ffff_ffff_ffff_ffff", Max},
+		{"18446744073709551615", uint128{0, ^uint64(0)}},
+		{"18446744073709551616", uint128{1, 0}},
+		{"340282366920938463463374607431768211455", Max},
+	}
+	for _, tt := range tests {
+		if got := MustParse(tt.s); got != tt.want {
+			t.Errorf("MustParse(%q) = %v; want %v", tt.s, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "-1", "+1", "12a", " 1", "0x", "1__0", "0_", "0o8", "340282366920938463463374607431768211456"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("MustParse(%q) did not panic", s)
+				}
+			}()
+			MustParse(s)
+		}()
+	}
+}
+
+func TestParseUint128(t *testing.T) {
+	tests := []struct {
+		s    string
+		base int
+		want uint128
+		err  error
+	}{
+		{"0", 10, uint128{}, nil},
+		{"ff", 16, uint128{0, 255}, nil},
+		{"FF", 16, uint128{0, 255}, nil},
+		{"zz", 36, uint128{0, 36*36 - 1}, nil},
+		{"1" + strings.Repeat("0", 64), 2, uint128{1, 0}, nil},
+		{"ffffffffffffffffffffffffffffffff", 16, Max, nil},
+		{"100000000000000000000000000000000", 16, Max, strconv.ErrRange},
+		{"340282366920938463463374607431768211456", 10, Max, strconv.ErrRange},
+		{"", 10, uint128{}, strconv.ErrSyntax},
+		{"12", 2, uint128{}, strconv.ErrSyntax},
+		{"-1", 10, uint128{}, strconv.ErrSyntax},
+		{"+1", 10, uint128{}, strconv.ErrSyntax},
+		{"1_0", 10, uint128{}, strconv.ErrSyntax},
+		{"Zz", 62, uint128{0, 61*62 + 35}, nil},
+		{"Z", 36, uint128{0, 35}, nil},
+
+		// Base 0 prefixes.
+		{"0", 0, uint128{}, nil},
+		{"42", 0, uint128{0, 42}, nil},
+		{"0x2a", 0, uint128{0, 42}, nil},
+		{"0X2A", 0, uint128{0, 42}, nil},
+		{"0b101010", 0, uint128{0, 42}, nil},
+		{"0o52", 0, uint128{0, 42}, nil},
+		{"052", 0, uint128{0, 42}, nil},
+		{"0x", 0, uint128{}, strconv.ErrSyntax},
+		{"08", 0, uint128{}, strconv.ErrSyntax},
+		{"0x1" + strings.Repeat("0", 32), 0, Max, strconv.ErrRange},
+
+		// Underscores, only for base 0 and only between digits.
+		{"1_000", 0, uint128{0, 1000}, nil},
+		{"0x_1_f", 0, uint128{0, 0x1f}, nil},
+		{"0b_1010_1010", 0, uint128{0, 0xaa}, nil},
+		{"0_7", 0, uint128{0, 7}, nil},
+		{"0o_17", 0, uint128{0, 15}, nil},
+		{"_1", 0, uint128{}, strconv.ErrSyntax},
+		{"1_", 0, uint128{}, strconv.ErrSyntax},
+		{"1__0", 0, uint128{}, strconv.ErrSyntax},
+		{"0x_", 0, uint128{}, strconv.ErrSyntax},
+		{"1_000", 10, uint128{}, strconv.ErrSyntax},
+		{"ff_ff", 16, uint128{}, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		got, err := ParseUint128(tt.s, tt.base)
+		if got != tt.want || !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("ParseUint128(%q, %d) = %v, %v; want %v, %v", tt.s, tt.base, got, err, tt.want, tt.err)
+		}
+		if err != nil {
+			var ne *strconv.NumError
+			if !errors.As(err, &ne) || ne.Func != "ParseUint128" || ne.Num != tt.s {
+				t.Errorf("ParseUint128(%q, %d) error = %#v; want *strconv.NumError", tt.s, tt.base, err)
+			}
+		}
+	}
+
+	for _, base := range []int{-1, 1, 63} {
+		if _, err := ParseUint128("1", base); err == nil {
+			t.Errorf("ParseUint128(\"1\", %d) succeeded; want invalid base error", base)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		base := 2 + r.Intn(35)
+		s := toBig(u).Text(base)
+		if got, err := ParseUint128(s, base); err != nil || got != u {
+			t.Fatalf("ParseUint128(%q, %d) = %v, %v; want %v", s, base, got, err, u)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		format, in string
+		want       uint128
+	}{
+		{"%d", "18446744073709551616", uint128{1, 0}},
+		{"%v", "  42", uint128{0, 42}},
+		{"%v", "0x2a", uint128{0, 42}},
+		{"%v", "1_000", uint128{0, 1000}},
+		{"%s", "0b101010", uint128{0, 42}},
+		{"%x", "2A", uint128{0, 42}},
+		{"%X", "ffffffffffffffffffffffffffffffff", Max},
+		{"%o", "52", uint128{0, 42}},
+		{"%b", "101010", uint128{0, 42}},
+	}
+	for _, tt := range tests {
+		var u uint128
+		if _, err := fmt.Sscanf(tt.in, tt.format, &u); err != nil || u != tt.want {
+			t.Errorf("Sscanf(%q, %q) = %v, %v; want %v", tt.in, tt.format, u, err, tt.want)
+		}
+	}
+
+	// Scanning stops at the first rune that is not a digit of the
+	// base, leaving the rest unread, as fmt does for integers.
+	for _, tt := range []struct {
+		format, in string
+		want       uint128
+		rest       string
+	}{
+		{"%d", "123abc", uint128{0, 123}, "abc"},
+		{"%d", "123_4", uint128{0, 123}, "_4"},
+		{"%d", "12.5", uint128{0, 12}, ".5"},
+		{"%b", "1012", uint128{0, 5}, "2"},
+		{"%o", "778", uint128{0, 63}, "8"},
+		{"%x", "ffg", uint128{0, 255}, "g"},
+		{"%v", "42abc", uint128{0, 42}, "abc"},
+		{"%v", "0x2ag", uint128{0, 42}, "g"},
+		{"%v", "0b102", uint128{0, 2}, "2"},
+		{"%v", "078", uint128{0, 7}, "8"},
+		{"%v", "1_000x", uint128{0, 1000}, "x"},
+	} {
+		var u uint128
+		var rest string
+		if n, err := fmt.Sscanf(tt.in, tt.format+"%s", &u, &rest); n != 2 || err != nil || u != tt.want || rest != tt.rest {
+			t.Errorf("Sscanf(%q, %q) = %v, %q, %v; want %v, %q", tt.in, tt.format+"%s", u, rest, err, tt.want, tt.rest)
+		}
+	}
+
+	var a, b uint128
+	var s string
+	if n, err := fmt.Sscan("340282366920938463463374607431768211455 7 end", &a, &b, &s); n != 3 || err != nil || a != Max || b != (uint128{0, 7}) || s != "end" {
+		t.Errorf("Sscan = %d, %v: %v %v %q", n, err, a, b, s)
+	}
+
+	for _, in := range []string{"", "-1", "zz", "340282366920938463463374607431768211456"} {
+		u := uint128{0, 99}
+		if _, err := fmt.Sscan(in, &u); err == nil {
+			t.Errorf("Sscan(%q) = %v; want error", in, u)
+		} else if u != (uint128{0, 99}) {
+			t.Errorf("Sscan(%q) failed but modified u to %v", in, u)
+		}
+	}
+	var u uint128
+	if _, err := fmt.Sscanf("1", "%c", &u); err == nil {
+		t.Errorf("Sscanf with %%c succeeded")
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		want := randUint128(r)
+		var got uint128
+		if _, err := fmt.Sscan(fmt.Sprintf("%#x", want), &got); err != nil || got != want {
+			t.Fatalf("Sscan(Sprintf(%%#x, %v)) = %v, %v", want, got, err)
+		}
+	}
+}
+
+func TestParseBitsErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		strings.Repeat("0", 127),
+		strings.Repeat("0", 129),
+		strings.Repeat("0", 127) + "2",
+		strings.Repeat("0", 127) + "-1",
+		"0b" + strings.Repeat("0", 128),
+	} {
+		if u, err := ParseBits(s); err == nil {
+			t.Errorf("ParseBits(%q) = %v; want error", s, u)
+		} else if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("ParseBits(%q) error = %v; want syntax error", s, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pgxuint128 integrates uint128.Uint128 with the pgx Postgres
+// driver, mapping it to the numeric type.
+//
+// Once Register has been called on a connection's type map, values of
+// type uint128.Uint128 and uint128.NullUint128 can be used directly as
+// query arguments and scan targets, using the binary numeric protocol
+// without a round trip through decimal strings:
+//
+//	pgxuint128.Register(conn.TypeMap())
+//
+//	var n uint128.Uint128
+//	err := conn.QueryRow(ctx, "select $1::numeric + 1", n).Scan(&n)
+//
+// With pgxpool, call Register from the pool's AfterConnect hook.
+package pgxuint128
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"uint128"
+)
+
+// Uint128 is a uint128.Uint128 that implements the pgtype numeric and
+// int8 scanner and valuer interfaces.
+type Uint128 uint128.Uint128
+
+// ScanNumeric implements pgtype.NumericScanner. It fails for NULL,
+// NaN, infinities, negative or fractional values and values of 2^128
+// or more.
+func (u *Uint128) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into *uint128.Uint128")
+	}
+	x, err := fromNumeric(v)
+	if err != nil {
+		return err
+	}
+	*u = Uint128(x)
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer.
+func (u Uint128) NumericValue() (pgtype.Numeric, error) {
+	b := uint128.Uint128(u).Bytes()
+	return pgtype.Numeric{Int: new(big.Int).SetBytes(b[:]), Valid: true}, nil
+}
+
+// ScanInt64 implements pgtype.Int64Scanner, for reading int2, int4 and
+// int8 columns.
+func (u *Uint128) ScanInt64(v pgtype.Int8) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into *uint128.Uint128")
+	}
+	x, ok := uint128.TryFrom(v.Int64)
+	if !ok {
+		return fmt.Errorf("cannot scan negative value %d into *uint128.Uint128", v.Int64)
+	}
+	*u = Uint128(x)
+	return nil
+}
+
+// NullUint128 is a uint128.NullUint128 that implements the pgtype
+// numeric and int8 scanner and valuer interfaces.
+type NullUint128 uint128.NullUint128
+
+// ScanNumeric implements pgtype.NumericScanner. NULL sets Valid to
+// false.
+func (n *NullUint128) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		*n = NullUint128{}
+		return nil
+	}
+	x, err := fromNumeric(v)
+	if err != nil {
+		return err
+	}
+	*n = NullUint128{Uint128: x, Valid: true}
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer.
+func (n NullUint128) NumericValue() (pgtype.Numeric, error) {
+	if !n.Valid {
+		return pgtype.Numeric{}, nil
+	}
+	return Uint128(n.Uint128).NumericValue()
+}
+
+// ScanInt64 implements pgtype.Int64Scanner.
+func (n *NullUint128) ScanInt64(v pgtype.Int8) error {
+	if !v.Valid {
+		*n = NullUint128{}
+		return nil
+	}
+	var u Uint128
+	if err := u.ScanInt64(v); err != nil {
+		return err
+	}
+	*n = NullUint128{Uint128: uint128.Uint128(u), Valid: true}
+	return nil
+}
+
+var (
+	bigTen = big.NewInt(10)
+	max128 = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// fromNumeric converts a valid numeric value to a Uint128.
+func fromNumeric(v pgtype.Numeric) (uint128.Uint128, error) {
+	switch {
+	case v.NaN:
+		return uint128.Uint128{}, fmt.Errorf("cannot scan NaN into *uint128.Uint128")
+	case v.InfinityModifier != pgtype.Finite:
+		return uint128.Uint128{}, fmt.Errorf("cannot scan %v into *uint128.Uint128", v.InfinityModifier)
+	}
+	x := new(big.Int).Set(v.Int)
+	if v.Exp > 0 {
+		// Numeric values come from a database, so exponents are
+		// bounded by Postgres's limits and this multiplication is
+		// cheap.
+		x.Mul(x, new(big.Int).Exp(bigTen, big.NewInt(int64(v.Exp)), nil))
+	} else if v.Exp < 0 {
+		var r big.Int
+		x.QuoRem(x, new(big.Int).Exp(bigTen, big.NewInt(-int64(v.Exp)), nil), &r)
+		if r.Sign() != 0 {
+			return uint128.Uint128{}, fmt.Errorf("cannot scan non-integer numeric into *uint128.Uint128")
+		}
+	}
+	if x.Sign() < 0 || x.Cmp(max128) >= 0 {
+		return uint128.Uint128{}, fmt.Errorf("numeric value %v out of range for *uint128.Uint128", x)
+	}
+	var b [16]byte
+	x.FillBytes(b[:])
+	return uint128.FromBytesBE(b), nil
+}
+
+// TryWrapEncodePlan is a pgtype.TryWrapEncodePlanFunc that encodes
+// uint128.Uint128 and uint128.NullUint128 values through Uint128 and
+// NullUint128. Register installs it.
+func TryWrapEncodePlan(value any) (plan pgtype.WrappedEncodePlanNextSetter, nextValue any, ok bool) {
+	switch value := value.(type) {
+	case uint128.Uint128:
+		return &wrapEncodePlan{}, Uint128(value), true
+	case uint128.NullUint128:
+		return &wrapNullEncodePlan{}, NullUint128(value), true
+	}
+	return nil, nil, false
+}
+
+type wrapEncodePlan struct{ next pgtype.EncodePlan }
+
+func (plan *wrapEncodePlan) SetNext(next pgtype.EncodePlan) { plan.next = next }
+
+func (plan *wrapEncodePlan) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	return plan.next.Encode(Uint128(value.(uint128.Uint128)), buf)
+}
+
+type wrapNullEncodePlan struct{ next pgtype.EncodePlan }
+
+func (plan *wrapNullEncodePlan) SetNext(next pgtype.EncodePlan) { plan.next = next }
+
+func (plan *wrapNullEncodePlan) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	return plan.next.Encode(NullUint128(value.(uint128.NullUint128)), buf)
+}
+
+// TryWrapScanPlan is a pgtype.TryWrapScanPlanFunc that scans into
+// *uint128.Uint128 and *uint128.NullUint128 through *Uint128 and
+// *NullUint128. Register installs it.
+func TryWrapScanPlan(target any) (plan pgtype.WrappedScanPlanNextSetter, nextDst any, ok bool) {
+	switch target := target.(type) {
+	case *uint128.Uint128:
+		return &wrapScanPlan{}, (*Uint128)(target), true
+	case *uint128.NullUint128:
+		return &wrapNullScanPlan{}, (*NullUint128)(target), true
+	}
+	return nil, nil, false
+}
+
+type wrapScanPlan struct{ next pgtype.ScanPlan }
+
+func (plan *wrapScanPlan) SetNext(next pgtype.ScanPlan) { plan.next = next }
+
+func (plan *wrapScanPlan) Scan(src []byte, dst any) error {
+	return plan.next.Scan(src, (*Uint128)(dst.(*uint128.Uint128)))
+}
+
+type wrapNullScanPlan struct{ next pgtype.ScanPlan }
+
+func (plan *wrapNullScanPlan) SetNext(next pgtype.ScanPlan) { plan.next = next }
+
+func (plan *wrapNullScanPlan) Scan(src []byte, dst any) error {
+	return plan.next.Scan(src, (*NullUint128)(dst.(*uint128.NullUint128)))
+}
+
+// Register registers the Uint128 integration with m: it installs
+// TryWrapEncodePlan and TryWrapScanPlan, and makes numeric the default
+// Postgres type of uint128.Uint128 and uint128.NullUint128.
+func Register(m *pgtype.Map) {
+	m.TryWrapEncodePlanFuncs = append([]pgtype.TryWrapEncodePlanFunc{TryWrapEncodePlan}, m.TryWrapEncodePlanFuncs...)
+	m.TryWrapScanPlanFuncs = append([]pgtype.TryWrapScanPlanFunc{TryWrapScanPlan}, m.TryWrapScanPlanFuncs...)
+
+	m.RegisterDefaultPgType(uint128.Uint128{}, "numeric")
+	m.RegisterDefaultPgType(&uint128.Uint128{}, "numeric")
+	m.RegisterDefaultPgType([]uint128.Uint128{}, "_numeric")
+	m.RegisterDefaultPgType(uint128.NullUint128{}, "numeric")
+	m.RegisterDefaultPgType(&uint128.NullUint128{}, "numeric")
+	m.RegisterDefaultPgType([]uint128.NullUint128{}, "_numeric")
+}
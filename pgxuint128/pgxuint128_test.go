@@ -0,0 +1,125 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgxuint128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"uint128"
+)
+
+func newMap() *pgtype.Map {
+	m := pgtype.NewMap()
+	Register(m)
+	return m
+}
+
+func TestRoundTrip(t *testing.T) {
+	m := newMap()
+	values := []uint128.Uint128{
+		uint128.Zero,
+		uint128.One,
+		uint128.New(0, 1e18),
+		uint128.New(1, 0),
+		uint128.MustParse("100000000000000000000000000000000000000"),
+		uint128.Max,
+	}
+	for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+		for _, u := range values {
+			buf, err := m.Encode(pgtype.NumericOID, format, u, nil)
+			if err != nil {
+				t.Fatalf("format %d: Encode(%v): %v", format, u, err)
+			}
+			var got uint128.Uint128
+			if err := m.Scan(pgtype.NumericOID, format, buf, &got); err != nil || got != u {
+				t.Errorf("format %d: Scan(Encode(%v)) = %v, %v", format, u, got, err)
+			}
+			var n uint128.NullUint128
+			if err := m.Scan(pgtype.NumericOID, format, buf, &n); err != nil || !n.Valid || n.Uint128 != u {
+				t.Errorf("format %d: Scan(Encode(%v)) into NullUint128 = %+v, %v", format, u, n, err)
+			}
+		}
+	}
+
+	// The binary encoding must match pgtype's own for the same number.
+	want, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, pgtype.Numeric{Int: new(big.Int).Lsh(big.NewInt(1), 100), Valid: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, uint128.One.Lsh(100), nil)
+	if err != nil || string(got) != string(want) {
+		t.Errorf("Encode(2^100) = %x, %v; want %x", got, err, want)
+	}
+}
+
+func TestNull(t *testing.T) {
+	m := newMap()
+	buf, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, uint128.NullUint128{}, nil)
+	if err != nil || buf != nil {
+		t.Fatalf("Encode(invalid NullUint128) = %x, %v; want nil", buf, err)
+	}
+	n := uint128.NullUint128{Uint128: uint128.One, Valid: true}
+	if err := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, nil, &n); err != nil || n.Valid {
+		t.Errorf("Scan(NULL) into NullUint128 = %+v, %v", n, err)
+	}
+	var u uint128.Uint128
+	if err := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, nil, &u); err == nil {
+		t.Errorf("Scan(NULL) into Uint128 succeeded")
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	m := newMap()
+	for _, text := range []string{"-1", "1.5", "NaN", "Infinity", "340282366920938463463374607431768211456"} {
+		for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+			var n pgtype.Numeric
+			if err := n.Scan(text); err != nil {
+				t.Fatalf("Numeric.Scan(%q): %v", text, err)
+			}
+			buf, err := m.Encode(pgtype.NumericOID, format, n, nil)
+			if err != nil {
+				t.Fatalf("Encode(%q): %v", text, err)
+			}
+			var u uint128.Uint128
+			if err := m.Scan(pgtype.NumericOID, format, buf, &u); err == nil {
+				t.Errorf("format %d: Scan(%q) = %v; want error", format, text, u)
+			}
+		}
+	}
+
+	// Integral values with a fractional representation or an exponent
+	// are accepted.
+	for _, tt := range []struct {
+		n    pgtype.Numeric
+		want uint64
+	}{
+		{pgtype.Numeric{Int: big.NewInt(42000), Exp: -3, Valid: true}, 42},
+		{pgtype.Numeric{Int: big.NewInt(42), Exp: 2, Valid: true}, 4200},
+	} {
+		var u Uint128
+		if err := u.ScanNumeric(tt.n); err != nil || uint128.Uint128(u) != uint128.New(0, tt.want) {
+			t.Errorf("ScanNumeric(%ve%d) = %v, %v; want %d", tt.n.Int, tt.n.Exp, uint128.Uint128(u), err, tt.want)
+		}
+	}
+}
+
+func TestInt8(t *testing.T) {
+	m := newMap()
+	buf, err := m.Encode(pgtype.Int8OID, pgtype.BinaryFormatCode, int64(42), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u uint128.Uint128
+	if err := m.Scan(pgtype.Int8OID, pgtype.BinaryFormatCode, buf, &u); err != nil || u != uint128.New(0, 42) {
+		t.Errorf("Scan(int8 42) = %v, %v", u, err)
+	}
+	buf, _ = m.Encode(pgtype.Int8OID, pgtype.BinaryFormatCode, int64(-1), nil)
+	if err := m.Scan(pgtype.Int8OID, pgtype.BinaryFormatCode, buf, &u); err == nil {
+		t.Errorf("Scan(int8 -1) succeeded")
+	}
+}
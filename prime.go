@@ -0,0 +1,236 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// smallPrimes are the primes used for trial division before the
+// probabilistic tests.
+var smallPrimes = [...]uint64{
+	2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47,
+	53, 59, 61, 67, 71, 73, 79, 83, 89, 97,
+}
+
+// mrBound is 3317044064679887385961981, the smallest strong
+// pseudoprime to all of the bases 2, 3, ..., 41 (Sorenson and
+// Webster, 2015). Miller-Rabin with those thirteen bases is therefore
+// a proof of primality below it.
+var mrBound = Uint128{0x2be69, 0x51adc5b22410a5fd}
+
+// IsPrime reports whether u is prime.
+//
+// After trial division it runs Miller-Rabin with the thirteen prime
+// bases up to 41, which is deterministic for u < 3.3×10^24 (about
+// 2^81). Above that bound no small deterministic witness set is known
+// to cover the full 128-bit range, so IsPrime additionally applies a
+// strong Lucas test, completing a Baillie-PSW test. No composite that
+// passes Baillie-PSW is known, and none exists below 2^64, but unlike
+// the smaller range this is not a proof.
+func (u Uint128) IsPrime() bool {
+	if u.hi == 0 && u.lo < 2 {
+		return false
+	}
+	for _, p := range smallPrimes {
+		if u.hi == 0 && u.lo == p {
+			return true
+		}
+		if _, r := u.Div64(p); r == 0 {
+			return false
+		}
+	}
+	if last := smallPrimes[len(smallPrimes)-1]; u.hi == 0 && u.lo < last*last {
+		return true
+	}
+
+	mt := NewMontgomery(u)
+	for _, a := range smallPrimes[:13] {
+		if !mt.millerRabin(Uint128{0, a}) {
+			return false
+		}
+	}
+	if u.Cmp(mrBound) < 0 {
+		return true
+	}
+	return mt.strongLucas()
+}
+
+// millerRabin reports whether the modulus n of mt is a strong probable
+// prime to base a. It requires n odd and a reduced modulo n.
+func (mt Montgomery) millerRabin(a Uint128) bool {
+	nm1 := mt.m.SubOne()
+	s := uint(nm1.TrailingZeros())
+	d := nm1.Rsh(s)
+	one, minusOne := mt.one, mt.ToMont(nm1)
+	x := mt.ExpMont(mt.ToMont(a), d)
+	if x == one || x == minusOne {
+		return true
+	}
+	for i := uint(1); i < s; i++ {
+		x = mt.MulMont(x, x)
+		if x == minusOne {
+			return true
+		}
+		if x == one {
+			return false
+		}
+	}
+	return false
+}
+
+// strongLucas reports whether the modulus n of mt is an "almost extra
+// strong" Lucas probable prime, using Baillie's parameters Q = 1 and
+// the smallest P = 3, 4, 5, ... with Jacobi(P²-4, n) = -1, as in
+// (*math/big.Int).ProbablyPrime. It requires n odd, greater than the
+// small primes and less than 2^128-1.
+func (mt Montgomery) strongLucas() bool {
+	n := mt.m
+	var p uint64
+	for p = 3; ; p++ {
+		if p == 40 && n.IsPerfectSquare() {
+			// Squares have no P with Jacobi(P²-4, n) = -1.
+			return false
+		}
+		j := Jacobi(Uint128{0, p*p - 4}, n)
+		if j == -1 {
+			break
+		}
+		if j == 0 {
+			// P²-4 shares a factor with n, which is larger than P²-4.
+			return false
+		}
+	}
+
+	// n+1 == s*2^r with s odd. Compute V(s) and V(s+1) with the
+	// ladder V(2k) = V(k)²-2, V(2k+1) = V(k)V(k+1)-P,
+	// in Montgomery form.
+	s := n.AddOne()
+	r := uint(s.TrailingZeros())
+	s = s.Rsh(r)
+	two, minusTwo, pm := mt.ToMont(Uint128{0, 2}), mt.ToMont(n.Sub(Uint128{0, 2})), mt.ToMont(Uint128{0, p})
+	vk, vk1 := two, pm
+	for i := s.Len() - 1; i >= 0; i-- {
+		if s.Rsh(uint(i)).lo&1 != 0 {
+			vk = mt.MulMont(vk, vk1).ModSub(pm, n)
+			vk1 = mt.MulMont(vk1, vk1).ModSub(two, n)
+		} else {
+			vk1 = mt.MulMont(vk, vk1).ModSub(pm, n)
+			vk = mt.MulMont(vk, vk).ModSub(two, n)
+		}
+	}
+
+	// If V(s) = ±2, n is a probable prime when also U(s) = 0, which is
+	// equivalent to P*V(s) = 2*V(s+1).
+	if vk == two || vk == minusTwo {
+		if mt.MulMont(vk, pm) == vk1.ModAdd(vk1, n) {
+			return true
+		}
+	}
+	// Otherwise n is a probable prime if V(s*2^t) = 0 for some t < r-1.
+	for t := uint(0); t+1 < r; t++ {
+		if vk.IsZero() {
+			return true
+		}
+		if vk == two {
+			// 2 is a fixed point of V(2k) = V(k)²-2.
+			return false
+		}
+		vk = mt.MulMont(vk, vk).ModSub(two, n)
+	}
+	return false
+}
+
+// wheelUp[r] and wheelDown[r] are the distances from the residue r
+// modulo 30 to the nearest residue, at or above and at or below r
+// respectively, that is coprime to 30. Only those residues can hold
+// primes larger than 5.
+var (
+	wheelUp   = [30]uint8{1, 0, 5, 4, 3, 2, 1, 0, 3, 2, 1, 0, 1, 0, 3, 2, 1, 0, 1, 0, 3, 2, 1, 0, 5, 4, 3, 2, 1, 0}
+	wheelDown = [30]uint8{1, 0, 1, 2, 3, 4, 5, 0, 1, 2, 3, 0, 1, 0, 1, 2, 3, 0, 1, 0, 1, 2, 3, 0, 1, 2, 3, 4, 5, 0}
+)
+
+// NextPrime returns the smallest prime greater than u. If there is
+// none below 2^128, that is if u >= 2^128-159, ok is false.
+func (u Uint128) NextPrime() (p Uint128, ok bool) {
+	if u.hi == 0 && u.lo < 7 {
+		for _, p := range smallPrimes[:4] {
+			if p > u.lo {
+				return Uint128{0, p}, true
+			}
+		}
+	}
+	// Visit only the candidates coprime to 30, starting above u.
+	c, ok := u.AddChecked(Uint128{0, 1})
+	_, r := c.Div64(30)
+	for ok {
+		d := uint64(wheelUp[r])
+		if c, ok = c.AddChecked(Uint128{0, d}); !ok {
+			break
+		}
+		if c.IsPrime() {
+			return c, true
+		}
+		r = (r + d + 1) % 30
+		c, ok = c.AddChecked(Uint128{0, 1})
+	}
+	return Uint128{}, false
+}
+
+// PrevPrime returns the largest prime less than u.
+// If there is none, that is if u <= 2, ok is false.
+func (u Uint128) PrevPrime() (p Uint128, ok bool) {
+	if u.hi == 0 && u.lo <= 7 {
+		for i := 3; i >= 0; i-- {
+			if p := smallPrimes[i]; p < u.lo {
+				return Uint128{0, p}, true
+			}
+		}
+		return Uint128{}, false
+	}
+	// Visit only the candidates coprime to 30, starting below u.
+	// Since u > 7, the scan stops at 7 at the latest.
+	c := u.SubOne()
+	_, r := c.Div64(30)
+	for {
+		d := uint64(wheelDown[r])
+		c = c.Sub(Uint128{0, d})
+		if c.IsPrime() {
+			return c, true
+		}
+		r = (r + 30 - d - 1) % 30
+		c = c.SubOne()
+	}
+}
+
+// RandPrime returns a uniformly random prime of exactly the given bit
+// length, reading randomness from rand, which is typically
+// crypto/rand.Reader. Primality is decided by IsPrime.
+//
+// RandPrime returns an error if bits < 2 or bits > 128, or if reading
+// from rand fails.
+func RandPrime(rand io.Reader, bits int) (Uint128, error) {
+	if bits < 2 || bits > 128 {
+		return Uint128{}, errors.New("uint128: prime size must be between 2 and 128 bits")
+	}
+	var buf [16]byte
+	for {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return Uint128{}, err
+		}
+		p := Uint128{binary.BigEndian.Uint64(buf[:8]), binary.BigEndian.Uint64(buf[8:])}
+		// Keep the low bits, set the top one so that p has exactly the
+		// requested length, and skip even candidates (except for 2).
+		p = p.And(LowMask(bits)).Or(Uint128{0, 1}.Lsh(uint(bits - 1)))
+		if bits > 2 {
+			p.lo |= 1
+		}
+		if p.IsPrime() {
+			return p, nil
+		}
+	}
+}
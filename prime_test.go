@@ -0,0 +1,174 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// mustBig parses a decimal string for test tables.
+func mustBig(s string) *big.Int {
+	b, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("bad test number " + s)
+	}
+	return b
+}
+
+func TestIsPrime(t *testing.T) {
+	tests := []struct {
+		n    string
+		want bool
+	}{
+		{"0", false},
+		{"1", false},
+		{"2", true},
+		{"3", true},
+		{"4", false},
+		{"97", true},
+		{"9409", false}, // 97²
+		{"9973", true},
+		{"561", false},                       // Carmichael
+		{"3215031751", false},                // strong pseudoprime to bases 2, 3, 5, 7
+		{"3825123056546413051", false},       // strong pseudoprime to bases 2..23
+		{"18446744073709551557", true},       // largest prime below 2^64
+		{"18446744073709551617", false},      // 2^64 + 1
+		{"318665857834031151167461", false},  // strong pseudoprime to bases 2..37
+		{"3317044064679887385961981", false}, // strong pseudoprime to bases 2..41
+		{"2305843009213693951", true},        // 2^61 - 1
+		{"618970019642690137449562111", true},
+		{"162259276829213363391578010288127", true},
+		{"170141183460469231731687303715884105727", true},  // 2^127 - 1
+		{"340282366920938463463374607431768211297", true},  // 2^128 - 159
+		{"340282366920938463463374607431768211455", false}, // 2^128 - 1
+		{"340282366920938463463374607431768211453", false},
+		{"18446744030759878681", false}, // (2^32-5)^2
+		{"340282366920938463463374607431768211456", false},
+	}
+	for _, tt := range tests {
+		b := mustBig(tt.n)
+		if b.BitLen() > 128 {
+			continue
+		}
+		u := fromBig(b)
+		if got := u.IsPrime(); got != tt.want {
+			t.Errorf("%s.IsPrime() = %v; want %v", tt.n, got, tt.want)
+		}
+	}
+
+	// Products of two primes near 2^64 are the hardest composites for
+	// trial division, and exercise the Lucas test above mrBound.
+	p, q := mustBig("18446744073709551557"), mustBig("18446744073709551533")
+	if u := fromBig(new(big.Int).Mul(p, q)); u.IsPrime() {
+		t.Errorf("%v.IsPrime() = true; want false", u)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		u := randUint128(rnd).Or(uint128{0, 1})
+		if got, want := u.IsPrime(), toBig(u).ProbablyPrime(20); got != want {
+			t.Errorf("%v.IsPrime() = %v; want %v", u, got, want)
+		}
+	}
+}
+
+func TestStrongLucas(t *testing.T) {
+	// Check the Lucas half of Baillie-PSW on its own, for large odd
+	// values free of small factors.
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; {
+		u := uint128{rnd.Uint64() | 1<<63, rnd.Uint64() | 1}
+		if new(big.Int).GCD(nil, nil, toBig(u), big.NewInt(307444891294245705)).Cmp(big.NewInt(1)) != 0 {
+			continue // shares a factor with 3·5·7·...·47
+		}
+		n++
+		if got, want := NewMontgomery(u).strongLucas(), toBig(u).ProbablyPrime(20); got != want {
+			t.Errorf("%v.strongLucas() = %v; want %v", u, got, want)
+		}
+	}
+}
+
+func TestNextPrevPrime(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	largest := uint128{^uint64(0), ^uint64(0) - 158} // 2^128 - 159; the next prime down is 2^128 - 173
+	tests := []struct {
+		u      uint128
+		next   uint128
+		nextOK bool
+		prev   uint128
+		prevOK bool
+	}{
+		{uint128{0, 0}, uint128{0, 2}, true, uint128{}, false},
+		{uint128{0, 1}, uint128{0, 2}, true, uint128{}, false},
+		{uint128{0, 2}, uint128{0, 3}, true, uint128{}, false},
+		{uint128{0, 3}, uint128{0, 5}, true, uint128{0, 2}, true},
+		{uint128{0, 5}, uint128{0, 7}, true, uint128{0, 3}, true},
+		{uint128{0, 7}, uint128{0, 11}, true, uint128{0, 5}, true},
+		{uint128{0, 8}, uint128{0, 11}, true, uint128{0, 7}, true},
+		{uint128{0, 30}, uint128{0, 31}, true, uint128{0, 29}, true},
+		{uint128{0, 89}, uint128{0, 97}, true, uint128{0, 83}, true},
+		{uint128{0, 113}, uint128{0, 127}, true, uint128{0, 109}, true},
+		{uint128{0, 18446744073709551557}, uint128{1, 13}, true, uint128{0, 18446744073709551533}, true},
+		{uint128{1, 0}, uint128{1, 13}, true, uint128{0, 18446744073709551557}, true},
+		{largest.SubOne(), largest, true, largest.Sub(uint128{0, 14}), true},
+		{largest, uint128{}, false, largest.Sub(uint128{0, 14}), true},
+		{max, uint128{}, false, largest, true},
+	}
+	for _, tt := range tests {
+		if p, ok := tt.u.NextPrime(); p != tt.next || ok != tt.nextOK {
+			t.Errorf("%v.NextPrime() = %v, %v; want %v, %v", tt.u, p, ok, tt.next, tt.nextOK)
+		}
+		if p, ok := tt.u.PrevPrime(); p != tt.prev || ok != tt.prevOK {
+			t.Errorf("%v.PrevPrime() = %v, %v; want %v, %v", tt.u, p, ok, tt.prev, tt.prevOK)
+		}
+	}
+
+	// Walk the primes below 2000 in both directions.
+	var primes []uint64
+	for n := uint64(2); n < 2000; n++ {
+		if big.NewInt(int64(n)).ProbablyPrime(0) {
+			primes = append(primes, n)
+		}
+	}
+	p := uint128{}
+	for _, want := range primes {
+		p, _ = p.NextPrime()
+		if p != (uint128{0, want}) {
+			t.Fatalf("NextPrime walk reached %v; want %d", p, want)
+		}
+	}
+	for i := len(primes) - 2; i >= 0; i-- {
+		p, _ = p.PrevPrime()
+		if p != (uint128{0, primes[i]}) {
+			t.Fatalf("PrevPrime walk reached %v; want %d", p, primes[i])
+		}
+	}
+}
+
+func TestRandPrime(t *testing.T) {
+	for _, bits := range []int{2, 3, 8, 63, 64, 65, 100, 127, 128} {
+		for i := 0; i < 5; i++ {
+			p, err := RandPrime(crand.Reader, bits)
+			if err != nil {
+				t.Fatalf("RandPrime(%d): %v", bits, err)
+			}
+			if p.Len() != bits || !toBig(p).ProbablyPrime(20) {
+				t.Errorf("RandPrime(%d) = %v; not a %d-bit prime", bits, p, bits)
+			}
+		}
+	}
+	for _, bits := range []int{-1, 0, 1, 129} {
+		if _, err := RandPrime(crand.Reader, bits); err == nil {
+			t.Errorf("RandPrime(%d) succeeded; want error", bits)
+		}
+	}
+	if _, err := RandPrime(bytes.NewReader(make([]byte, 15)), 64); err == nil {
+		t.Errorf("RandPrime with short reader succeeded; want error")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Protocol Buffers wire types, as in google.golang.org/protobuf/encoding/protowire.
+const (
+	protoFixed64Type = 1
+	protoBytesType   = 2
+)
+
+// ConsumeVarint decodes a varint from the start of b, as written by
+// AppendVarint, and returns it together with the number of bytes read.
+// It returns an error if b is truncated or the value exceeds 128 bits.
+func ConsumeVarint(b []byte) (u Uint128, n int, err error) {
+	u, n = Uvarint(b)
+	switch {
+	case n == 0:
+		return Uint128{}, 0, errors.New("uint128: truncated varint")
+	case n < 0:
+		return Uint128{}, 0, errVarintOverflow
+	}
+	return u, n, nil
+}
+
+// AppendProtoBytes appends u to dst as the value of a length-delimited
+// (bytes) field, that is a length of 16 followed by the big-endian
+// bytes returned by Bytes, and returns the extended buffer. The field
+// tag is written separately, for example with protowire.AppendTag and
+// protowire.BytesType.
+func (u Uint128) AppendProtoBytes(dst []byte) []byte {
+	return u.AppendBytes(append(dst, 16))
+}
+
+// ConsumeProtoBytes decodes the value of a length-delimited field from
+// the start of b, as written by AppendProtoBytes, and returns it
+// together with the number of bytes read. The length must be 16.
+func ConsumeProtoBytes(b []byte) (u Uint128, n int, err error) {
+	if len(b) == 0 || b[0] != 16 {
+		return Uint128{}, 0, errors.New("uint128: bytes field is not 16 bytes long")
+	}
+	if len(b) < 17 {
+		return Uint128{}, 0, errors.New("uint128: truncated bytes field")
+	}
+	return FromBytesBE([16]byte(b[1:])), 17, nil
+}
+
+// AppendProtoBytesField appends a complete bytes field with number num
+// holding u, in the form of AppendProtoBytes.
+func (u Uint128) AppendProtoBytesField(dst []byte, num int32) []byte {
+	return u.AppendProtoBytes(appendProtoTag(dst, num, protoBytesType))
+}
+
+// AppendProtoFixed64Fields appends u as two fixed64 fields, the high
+// 64 bits with number hiNum and the low 64 bits with number loNum, and
+// returns the extended buffer. A decoder reads the fields in any order
+// and combines them with New.
+func (u Uint128) AppendProtoFixed64Fields(dst []byte, hiNum, loNum int32) []byte {
+	dst = binary.LittleEndian.AppendUint64(appendProtoTag(dst, hiNum, protoFixed64Type), u.hi)
+	return binary.LittleEndian.AppendUint64(appendProtoTag(dst, loNum, protoFixed64Type), u.lo)
+}
+
+// appendProtoTag appends the tag of field num with wire type typ.
+func appendProtoTag(dst []byte, num int32, typ byte) []byte {
+	return binary.AppendUvarint(dst, uint64(num)<<3|uint64(typ))
+}
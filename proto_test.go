@@ -0,0 +1,73 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestProtoFields(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		u := randUint128(r)
+		var b []byte
+		b = u.AppendProtoBytesField(b, 1)
+		b = u.AppendProtoFixed64Fields(b, 2, 3)
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = u.AppendProtoBytes(b)
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendBytes(b, u.AppendVarint(nil))
+
+		var hi, lo uint64
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			if n < 0 {
+				t.Fatal(protowire.ParseError(n))
+			}
+			b = b[n:]
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				t.Fatal(protowire.ParseError(n))
+			}
+			switch num {
+			case 1, 4:
+				got, m, err := ConsumeProtoBytes(b)
+				if typ != protowire.BytesType || err != nil || got != u || m != n {
+					t.Fatalf("field %d: ConsumeProtoBytes(%x) = %v, %d, %v; want %v, %d", num, b[:n], got, m, err, u, n)
+				}
+			case 2, 3:
+				if typ != protowire.Fixed64Type {
+					t.Fatalf("field %d has wire type %d; want fixed64", num, typ)
+				}
+				v, _ := protowire.ConsumeFixed64(b)
+				if num == 2 {
+					hi = v
+				} else {
+					lo = v
+				}
+			case 5:
+				v, _ := protowire.ConsumeBytes(b)
+				if got, m, err := ConsumeVarint(v); err != nil || got != u || m != len(v) {
+					t.Fatalf("ConsumeVarint(%x) = %v, %d, %v; want %v", v, got, m, err, u)
+				}
+			}
+			b = b[n:]
+		}
+		if got := New(hi, lo); got != u {
+			t.Fatalf("fixed64 fields = %v; want %v", got, u)
+		}
+	}
+
+	for _, in := range []string{"", "0f", "10", "1000"} {
+		b, _ := hex.DecodeString(in)
+		if got, n, err := ConsumeProtoBytes(b); err == nil {
+			t.Errorf("ConsumeProtoBytes(%s) = %v, %d; want error", in, got, n)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// Isqrt returns floor(sqrt(u)).
+func (u Uint128) Isqrt() Uint128 {
+	if u.hi == 0 && u.lo < 2 {
+		return u
+	}
+	// Newton's iteration x' = (x + u/x) / 2 decreases monotonically to
+	// the floor of the square root when started from any x >= sqrt(u),
+	// such as 2^ceil(len(u)/2).
+	k := uint(u.Len()+1) / 2
+	x := Uint128{0, 1 << k}
+	if k == 64 {
+		x = Uint128{1, 0}
+	}
+	for {
+		y := x.Midpoint(u.Div(x))
+		if y.Cmp(x) >= 0 {
+			return x
+		}
+		x = y
+	}
+}
+
+// IsPerfectSquare reports whether u is the square of an integer.
+func (u Uint128) IsPerfectSquare() bool {
+	// Squares are 0, 1, 4 or 9 modulo 16; this rejects 75% of
+	// non-squares without a square root.
+	if (0x0213>>(u.lo&15))&1 == 0 {
+		return false
+	}
+	r := u.Isqrt()
+	return r.Mul(r) == u
+}
+
+// Root returns floor(u**(1/n)), the integer nth root of u.
+// It panics if n is zero.
+func (u Uint128) Root(n uint) Uint128 {
+	switch {
+	case n == 0:
+		panic("uint128: zeroth root")
+	case n == 1:
+		return u
+	case n == 2:
+		return u.Isqrt()
+	}
+	// The root has at most ceil(len(u)/n) <= 43 bits. Determine them
+	// from the top down, keeping each bit whose inclusion does not
+	// push r**n above u.
+	l := uint(u.Len())
+	e := Uint128{0, uint64(n)}
+	var r uint64
+	for k := int((l + n - 1) / n); k >= 0; k-- {
+		c := r | 1<<uint(k)
+		if p, ok := (Uint128{0, c}).Exp(e); ok && p.Cmp(u) <= 0 {
+			r = c
+		}
+	}
+	return Uint128{0, r}
+}
+
+// Log2 returns floor(log2(u)), the index of the highest set bit
+// counting from the least significant end, or -1 if u is 0.
+func (u Uint128) Log2() int {
+	return u.Len() - 1
+}
+
+// Log10 returns floor(log10(u)), or -1 if u is 0.
+func (u Uint128) Log10() int {
+	if u.IsZero() {
+		return -1
+	}
+	// 1233/4096 approximates log10(2) closely enough that this is
+	// either the answer or one too large for all 128-bit values.
+	t := u.Len() * 1233 >> 12
+	if t >= len(pow10tab) || u.Cmp(pow10tab[t]) < 0 {
+		t--
+	}
+	return t
+}
+
+// DigitCount returns the number of decimal digits in u, which is
+// len(u.String()). It is 1 for 0.
+func (u Uint128) DigitCount() int {
+	return max(u.Log10()+1, 1)
+}
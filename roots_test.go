@@ -0,0 +1,116 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestIsqrt(t *testing.T) {
+	tests := []uint128{
+		{0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 15}, {0, 16}, {0, 17},
+		{0, ^uint64(0)}, {1, 0}, {1, 1},
+		{0xfffffffffffffffe, 1}, // (2^64-1)^2
+		{0xfffffffffffffffe, 0},
+		{0xfffffffffffffffe, 2},
+		{^uint64(0), ^uint64(0)},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		tests = append(tests, randUint128(rnd))
+	}
+	for _, u := range tests {
+		want := new(big.Int).Sqrt(toBig(u))
+		got := u.Isqrt()
+		if toBig(got).Cmp(want) != 0 {
+			t.Errorf("%v.Isqrt() = %v; want %v", u, got, fromBig(want))
+		}
+		wantSquare := new(big.Int).Mul(want, want).Cmp(toBig(u)) == 0
+		if got := u.IsPerfectSquare(); got != wantSquare {
+			t.Errorf("%v.IsPerfectSquare() = %v; want %v", u, got, wantSquare)
+		}
+		if sq := u.Mul(u); u.hi == 0 && !sq.IsPerfectSquare() {
+			t.Errorf("%v.IsPerfectSquare() = false; want true", sq)
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	tests := []uint128{
+		{0, 0}, {0, 1}, {0, 7}, {0, 8}, {0, 9}, {0, 26}, {0, 27}, {0, 28},
+		{0, ^uint64(0)}, {1, 0}, {^uint64(0), ^uint64(0)},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		tests = append(tests, randUint128(rnd))
+	}
+	for _, u := range tests {
+		for _, n := range []uint{1, 2, 3, 4, 5, 7, 10, 63, 64, 127, 128, 129, 1000} {
+			got := u.Root(n)
+			// Check got**n <= u < (got+1)**n.
+			bn := big.NewInt(int64(n))
+			lo := new(big.Int).Exp(toBig(got), bn, nil)
+			hi := new(big.Int).Exp(new(big.Int).Add(toBig(got), big.NewInt(1)), bn, nil)
+			if lo.Cmp(toBig(u)) > 0 || hi.Cmp(toBig(u)) <= 0 {
+				t.Errorf("%v.Root(%d) = %v; out of range", u, n, got)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Root(0) did not panic")
+		}
+	}()
+	uint128{0, 1}.Root(0)
+}
+
+func TestLogs(t *testing.T) {
+	tests := []struct {
+		u           uint128
+		log2, log10 int
+		digits      int
+	}{
+		{uint128{}, -1, -1, 1},
+		{uint128{0, 1}, 0, 0, 1},
+		{uint128{0, 9}, 3, 0, 1},
+		{uint128{0, 10}, 3, 1, 2},
+		{uint128{0, 1e19 - 1}, 63, 18, 19},
+		{uint128{0, 1e19}, 63, 19, 20},
+		{uint128{1, 0}, 64, 19, 20},
+		{pow10tab[38].SubOne(), 126, 37, 38},
+		{pow10tab[38], 126, 38, 39},
+		{Max, 127, 38, 39},
+	}
+	for _, tt := range tests {
+		if got := tt.u.Log2(); got != tt.log2 {
+			t.Errorf("%v.Log2() = %d; want %d", tt.u, got, tt.log2)
+		}
+		if got := tt.u.Log10(); got != tt.log10 {
+			t.Errorf("%v.Log10() = %d; want %d", tt.u, got, tt.log10)
+		}
+		if got := tt.u.DigitCount(); got != tt.digits {
+			t.Errorf("%v.DigitCount() = %d; want %d", tt.u, got, tt.digits)
+		}
+	}
+
+	// Check every power of ten and its neighbors, plus random values.
+	for _, p := range pow10tab {
+		for _, u := range []uint128{p.SubOne(), p, p.AddOne()} {
+			if got, want := u.DigitCount(), len(u.String()); got != want {
+				t.Errorf("%v.DigitCount() = %d; want %d", u, got, want)
+			}
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		if got, want := u.DigitCount(), len(u.String()); got != want {
+			t.Fatalf("%v.DigitCount() = %d; want %d", u, got, want)
+		}
+	}
+}
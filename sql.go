@@ -0,0 +1,101 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// Value implements driver.Valuer. It stores u as its decimal string,
+// which databases convert to NUMERIC(39,0) or DECIMAL(39,0) columns.
+// To store the 16-byte binary form instead, convert u to Binary.
+//
+// Uint128 cannot implement sql.Scanner itself, because its Scan method
+// implements fmt.Scanner; scan into a NullUint128 or a Binary instead.
+func (u Uint128) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// NullUint128 represents a Uint128 that may be NULL, in the manner of
+// sql.NullInt64. It implements sql.Scanner and driver.Valuer, and is
+// also the way to scan a NOT NULL column into a Uint128.
+type NullUint128 struct {
+	Uint128 Uint128
+	Valid   bool // Valid is true if Uint128 is not NULL
+}
+
+// Scan implements sql.Scanner. It accepts nil, a non-negative int64,
+// and a string or []byte holding decimal text (or hexadecimal text
+// with a 0x prefix), as returned for NUMERIC columns. A []byte is
+// always read as text, even when it is 16 bytes long; scan columns
+// holding the binary form into a Binary instead.
+func (n *NullUint128) Scan(src any) error {
+	var (
+		u   Uint128
+		err error
+	)
+	switch src := src.(type) {
+	case nil:
+		*n = NullUint128{}
+		return nil
+	case int64:
+		var ok bool
+		if u, ok = TryFrom(src); !ok {
+			err = errors.New("uint128: cannot scan negative value " + fmt.Sprint(src))
+		}
+	case string:
+		u, err = parseText(src)
+	case []byte:
+		u, err = parseText(string(src))
+	default:
+		err = fmt.Errorf("uint128: cannot scan type %T into NullUint128", src)
+	}
+	if err != nil {
+		return err
+	}
+	*n = NullUint128{u, true}
+	return nil
+}
+
+// Value implements driver.Valuer, storing NULL if n is not valid and
+// the decimal string otherwise.
+func (n NullUint128) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Uint128.Value()
+}
+
+// Binary is a Uint128 stored in the database as its 16-byte big-endian
+// encoding, for BINARY(16), BYTEA or BLOB columns. Bytewise comparison
+// of the stored values matches numeric order. Convert with Binary(u)
+// and Uint128(b).
+type Binary Uint128
+
+// Value implements driver.Valuer.
+func (b Binary) Value() (driver.Value, error) {
+	return Uint128(b).AppendBytes(make([]byte, 0, 16)), nil
+}
+
+// Scan implements sql.Scanner. It accepts exactly 16 bytes, as a []byte
+// or string.
+func (b *Binary) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("uint128: cannot scan type %T into Binary", src)
+	}
+	if len(data) != 16 {
+		return fmt.Errorf("uint128: cannot scan %d bytes into Binary; want 16", len(data))
+	}
+	*b = Binary(FromBytesBE([16]byte(data)))
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math/rand"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Uint128{}
+	_ driver.Valuer = NullUint128{}
+	_ sql.Scanner   = (*NullUint128)(nil)
+	_ driver.Valuer = Binary{}
+	_ sql.Scanner   = (*Binary)(nil)
+)
+
+func TestSQLValue(t *testing.T) {
+	if v, err := Max.Value(); err != nil || v != "340282366920938463463374607431768211455" {
+		t.Errorf("Max.Value() = %v, %v", v, err)
+	}
+	if v, err := (NullUint128{}).Value(); err != nil || v != nil {
+		t.Errorf("invalid NullUint128.Value() = %v, %v; want nil", v, err)
+	}
+	if v, err := (NullUint128{One, true}).Value(); err != nil || v != "1" {
+		t.Errorf("NullUint128.Value() = %v, %v; want 1", v, err)
+	}
+	if v, err := Binary(One).Value(); err != nil || string(v.([]byte)) != "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01" {
+		t.Errorf("Binary.Value() = %v, %v", v, err)
+	}
+	// Values must be types the driver package accepts.
+	for _, v := range []driver.Valuer{Max, NullUint128{Max, true}, Binary(Max)} {
+		if _, err := driver.DefaultParameterConverter.ConvertValue(v); err != nil {
+			t.Errorf("ConvertValue(%T) failed: %v", v, err)
+		}
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	digits16 := []byte("1234567890123456")
+	tests := []struct {
+		src  any
+		want NullUint128
+	}{
+		{nil, NullUint128{}},
+		{int64(0), NullUint128{Zero, true}},
+		{int64(42), NullUint128{uint128{0, 42}, true}},
+		{"340282366920938463463374607431768211455", NullUint128{Max, true}},
+		{[]byte("18446744073709551616"), NullUint128{uint128{1, 0}, true}},
+		{"0xff", NullUint128{uint128{0, 255}, true}},
+		{digits16, NullUint128{uint128{0, 1234567890123456}, true}},
+	}
+	for _, tt := range tests {
+		n := NullUint128{uint128{0, 7}, true}
+		if err := n.Scan(tt.src); err != nil || n != tt.want {
+			t.Errorf("Scan(%#v) = %+v, %v; want %+v", tt.src, n, err, tt.want)
+		}
+	}
+	max := Max.Bytes()
+	for _, src := range []any{int64(-1), "", "-1", "1.5", []byte("abc"), max[:], 1.0, true} {
+		var n NullUint128
+		if err := n.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) = %+v; want error", src, n)
+		}
+	}
+
+	// A 16-byte []byte is text to NullUint128 and binary to Binary,
+	// even when its bytes happen to be ASCII digits.
+	var n NullUint128
+	var b Binary
+	if err := n.Scan(digits16); err != nil || n.Uint128 != (uint128{0, 1234567890123456}) {
+		t.Errorf("NullUint128.Scan(%q) = %v, %v", digits16, n.Uint128, err)
+	}
+	if err := b.Scan(digits16); err != nil || Uint128(b) != FromBytesBE([16]byte(digits16)) {
+		t.Errorf("Binary.Scan(%q) = %v, %v", digits16, Uint128(b), err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		u := randUint128(r)
+		v, _ := Binary(u).Value()
+		var b Binary
+		if err := b.Scan(v); err != nil || Uint128(b) != u {
+			t.Fatalf("Binary round trip of %v = %v, %v", u, Uint128(b), err)
+		}
+		v, _ = u.Value()
+		var n NullUint128
+		if err := n.Scan(v); err != nil || !n.Valid || n.Uint128 != u {
+			t.Fatalf("NullUint128 round trip of %v = %+v, %v", u, n, err)
+		}
+	}
+	for _, src := range []any{nil, []byte{1, 2}, "short", int64(1)} {
+		var b Binary
+		if err := b.Scan(src); err == nil {
+			t.Errorf("Binary.Scan(%#v) succeeded", src)
+		}
+	}
+}
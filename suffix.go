@@ -0,0 +1,359 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrNotInteger is the strconv.NumError.Err value reported when a
+// string denotes a number that is not a whole number, such as
+// "1.5" or "1.3Ki".
+var ErrNotInteger = errors.New("value is not an integer")
+
+// pow10tab[i] is 10^i; 10^38 is the largest power of ten below 2^128.
+var pow10tab = func() (t [39]Uint128) {
+	t[0] = One
+	for i := 1; i < len(t); i++ {
+		t[i] = t[i-1].Mul64(10)
+	}
+	return t
+}()
+
+// A unitSuffix is a multiplier suffix, such as "Ki" for 2^10.
+type unitSuffix struct {
+	name  string
+	scale Uint128
+}
+
+// iecUnits and siUnits list the binary and decimal multipliers in
+// increasing order.
+var (
+	iecUnits = []unitSuffix{
+		{"Ki", One.Lsh(10)},
+		{"Mi", One.Lsh(20)},
+		{"Gi", One.Lsh(30)},
+		{"Ti", One.Lsh(40)},
+		{"Pi", One.Lsh(50)},
+		{"Ei", One.Lsh(60)},
+		{"Zi", One.Lsh(70)},
+		{"Yi", One.Lsh(80)},
+	}
+	siUnits = []unitSuffix{
+		{"k", pow10tab[3]},
+		{"M", pow10tab[6]},
+		{"G", pow10tab[9]},
+		{"T", pow10tab[12]},
+		{"P", pow10tab[15]},
+		{"E", pow10tab[18]},
+		{"Z", pow10tab[21]},
+		{"Y", pow10tab[24]},
+		{"R", pow10tab[27]},
+		{"Q", pow10tab[30]},
+	}
+)
+
+// unitSuffixes lists the suffixes accepted by ParseWithSuffix, binary
+// ones first so that "Ki" is not mistaken for "K" followed by junk.
+var unitSuffixes = append(append(append([]unitSuffix{}, iecUnits...), siUnits...), unitSuffix{"K", pow10tab[3]})
+
+// ParseWithSuffix parses a decimal number with an optional SI or IEC
+// multiplier suffix, such as "64Ki" (65536), "3E" (3*10^18) or "1.5Yi"
+// (1.5*2^80). The SI suffixes are k (or K), M, G, T, P, E, Z, Y, R and
+// Q for 10^3 to 10^30; the IEC suffixes are Ki, Mi, Gi, Ti, Pi, Ei, Zi
+// and Yi for 2^10 to 2^80. The number may have a fractional part, but
+// the scaled value must be a whole number.
+//
+// Errors are of type *strconv.NumError with Func "ParseWithSuffix".
+// err.Err is strconv.ErrSyntax for malformed input, ErrNotInteger if
+// the scaled value has a fractional part, and strconv.ErrRange if it
+// does not fit in 128 bits (in which case Max is returned).
+func ParseWithSuffix(s string) (Uint128, error) {
+	const fnParseSuffix = "ParseWithSuffix"
+
+	num, scale := s, One
+	for _, u := range unitSuffixes {
+		if len(s) > len(u.name) && s[len(s)-len(u.name):] == u.name {
+			num, scale = s[:len(s)-len(u.name)], u.scale
+			break
+		}
+	}
+
+	d, exp, ok := parseFixed(num)
+	if !ok {
+		return Uint128{}, syntaxError(fnParseSuffix, s)
+	}
+	return scaled(fnParseSuffix, s, d, exp, scale)
+}
+
+// ParseFloatLike parses a decimal number in the notation of floating-
+// point literals, such as "1e30", "2.5e20", "1.5E+3" or "12", and
+// returns its value if it is a whole number that fits in 128 bits.
+// Unlike with strconv.ParseFloat, the result is exact: "2.5e20" is
+// precisely 250000000000000000000, and "1.5e0" is rejected.
+//
+// Errors are of type *strconv.NumError with Func "ParseFloatLike".
+// err.Err is strconv.ErrSyntax for malformed input, ErrNotInteger if
+// the value has a fractional part, and strconv.ErrRange if it does
+// not fit in 128 bits (in which case Max is returned).
+func ParseFloatLike(s string) (Uint128, error) {
+	const fnParseFloat = "ParseFloatLike"
+
+	mant, exp := s, 0
+	for i := 0; i < len(s); i++ {
+		if lower(s[i]) == 'e' {
+			var ok bool
+			if exp, ok = parseExp(s[i+1:]); !ok {
+				return Uint128{}, syntaxError(fnParseFloat, s)
+			}
+			mant = s[:i]
+			break
+		}
+	}
+	d, e, ok := parseFixed(mant)
+	if !ok {
+		return Uint128{}, syntaxError(fnParseFloat, s)
+	}
+	return scaled(fnParseFloat, s, d, e+exp, One)
+}
+
+// parseExp parses a decimal exponent with an optional sign. Values
+// too large to matter saturate at ±1e6.
+func parseExp(s string) (exp int, ok bool) {
+	neg := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		neg, s = s[0] == '-', s[1:]
+	}
+	if s == "" {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if exp < 1e6 {
+			exp = exp*10 + int(c-'0')
+		}
+	}
+	if neg {
+		exp = -exp
+	}
+	return exp, true
+}
+
+// scaled returns d*10^exp*scale, where d holds decimal digits without
+// trailing zeros, for the parser fn of s, or a *strconv.NumError if the
+// result overflows or is not a whole number.
+func scaled(fn, s string, d []byte, exp int, scale Uint128) (Uint128, error) {
+	m, ok := digitsValue(d)
+	if !ok && scale != One {
+		// Too many digits for 128 bits, but the scale may cancel a
+		// fractional part, as in "0.5Ki": multiply it out first.
+		d, exp = mulDigits(d, scale, exp)
+		m, ok = digitsValue(d)
+		scale = One
+	}
+	var err error
+	switch {
+	case !ok && exp < 0:
+		// d is at least 2^128 and its last digit is nonzero, so the
+		// value has a fractional part.
+		err = ErrNotInteger
+	case !ok:
+		err = strconv.ErrRange
+	case m.IsZero():
+		// 0 with any exponent is 0.
+	case exp < 0:
+		m, err = scaleExact(m, scale, -exp)
+	case exp >= len(pow10tab):
+		err = strconv.ErrRange
+	default:
+		var ok bool
+		if m, ok = m.MulChecked(pow10tab[exp]); ok {
+			m, ok = m.MulChecked(scale)
+		}
+		if !ok {
+			err = strconv.ErrRange
+		}
+	}
+	switch err {
+	case nil:
+		return m, nil
+	case strconv.ErrRange:
+		return Max, &strconv.NumError{Func: fn, Num: s, Err: err}
+	}
+	return Uint128{}, &strconv.NumError{Func: fn, Num: s, Err: err}
+}
+
+// scaleExact returns m*scale/10^frac, or strconv.ErrRange if that
+// does not fit in 128 bits, or ErrNotInteger if it is not a whole
+// number.
+func scaleExact(m, scale Uint128, frac int) (Uint128, error) {
+	const maxPow = len(pow10tab) - 1
+	q, r, ok := MulDivRem(m, scale, pow10tab[min(frac, maxPow)])
+	for frac -= maxPow; r.IsZero() && frac > 0; frac -= maxPow {
+		q, r = q.QuoRem(pow10tab[min(frac, maxPow)])
+	}
+	switch {
+	case !r.IsZero():
+		return Uint128{}, ErrNotInteger
+	case !ok:
+		return Uint128{}, strconv.ErrRange
+	}
+	return q, nil
+}
+
+// parseFixed parses a decimal number with an optional fractional part,
+// "ddd", "ddd." or "ddd.ddd" (but not "."), as d*10^exp, where the
+// digits d have neither leading nor trailing zeros.
+func parseFixed(s string) (d []byte, exp int, ok bool) {
+	intPart, fracPart := s, ""
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			intPart, fracPart = s[:i], s[i+1:]
+			break
+		}
+	}
+	if intPart == "" && fracPart == "" {
+		return nil, 0, false
+	}
+	d = make([]byte, 0, 64)
+	for _, part := range []string{intPart, fracPart} {
+		for i := 0; i < len(part); i++ {
+			c := part[i]
+			if c < '0' || c > '9' {
+				return nil, 0, false
+			}
+			if c != '0' || len(d) > 0 {
+				d = append(d, c)
+			}
+		}
+	}
+	exp = -len(fracPart)
+	for len(d) > 0 && d[len(d)-1] == '0' {
+		d = d[:len(d)-1]
+		exp++
+	}
+	return d, exp, true
+}
+
+// digitsValue returns the value of the decimal digits d, or false if
+// it does not fit in 128 bits.
+func digitsValue(d []byte) (m Uint128, ok bool) {
+	if len(d) > SortableLen {
+		return Uint128{}, false
+	}
+	for _, c := range d {
+		if m, ok = m.Mul64Checked(10); ok {
+			m, ok = m.AddChecked(Uint128{0, uint64(c - '0')})
+		}
+		if !ok {
+			return Uint128{}, false
+		}
+	}
+	return m, true
+}
+
+// mulDigits returns the decimal digits of d*scale*10^exp as digits
+// without trailing zeros and an adjusted exponent. It requires
+// scale < 2^124, so that the carry stays within 128 bits.
+func mulDigits(d []byte, scale Uint128, exp int) ([]byte, int) {
+	var carry Uint128
+	for i := len(d) - 1; i >= 0; i-- {
+		var r uint64
+		carry, r = scale.Mul64(uint64(d[i] - '0')).Add(carry).Div64(10)
+		d[i] = '0' + byte(r)
+	}
+	var head []byte
+	for !carry.IsZero() {
+		var r uint64
+		carry, r = carry.Div64(10)
+		head = append(head, '0'+byte(r))
+	}
+	for i, j := 0, len(head)-1; i < j; i, j = i+1, j-1 {
+		head[i], head[j] = head[j], head[i]
+	}
+	d = append(head, d...)
+	for len(d) > 0 && d[len(d)-1] == '0' {
+		d = d[:len(d)-1]
+		exp++
+	}
+	return d, exp
+}
+
+// maxHumanPrec is the largest precision accepted by FormatSI and
+// FormatIEC; it keeps the scaled mantissa within 128 bits.
+const maxHumanPrec = 19
+
+// Humanize returns a short human-readable form of u using decimal SI
+// multipliers and at most one fractional digit, such as "3.4Z" or
+// "999". It is shorthand for u.FormatSI(1).
+func (u Uint128) Humanize() string {
+	return u.FormatSI(1)
+}
+
+// FormatSI formats u with the largest SI multiplier suffix (k, M, G,
+// T, P, E, Z, Y, R or Q, for 10^3 to 10^30) that keeps the mantissa at
+// least 1, rounding the mantissa half up to at most prec fractional
+// digits and dropping trailing zeros: 1234567 with prec 2 is "1.23M".
+// Values below 1000 are formatted exactly, without a suffix. prec is
+// clamped to [0, 19]. The result can be read back (with the rounding
+// error) by ParseWithSuffix.
+func (u Uint128) FormatSI(prec int) string {
+	return u.formatUnits(siUnits, 1000, prec)
+}
+
+// FormatIEC is like FormatSI but uses binary IEC multipliers (Ki, Mi,
+// Gi, Ti, Pi, Ei, Zi and Yi, for 2^10 to 2^80): 120<<40 is "120Ti".
+func (u Uint128) FormatIEC(prec int) string {
+	return u.formatUnits(iecUnits, 1024, prec)
+}
+
+// formatUnits implements FormatSI and FormatIEC; step is the ratio
+// between consecutive units.
+func (u Uint128) formatUnits(units []unitSuffix, step uint64, prec int) string {
+	prec = max(0, min(prec, maxHumanPrec))
+	i := len(units) - 1
+	for i >= 0 && u.Cmp(units[i].scale) < 0 {
+		i--
+	}
+	if i < 0 {
+		return u.String()
+	}
+	p := pow10tab[prec]
+	var q Uint128
+	for {
+		var r Uint128
+		q, r, _ = MulDivRem(u, p, units[i].scale)
+		if r.Cmp(units[i].scale.Sub(r)) >= 0 {
+			q = q.AddOne()
+		}
+		// Rounding may carry the mantissa up to step, as in 999.96k
+		// with prec 1; use the next unit then, if there is one.
+		if i == len(units)-1 || q.Cmp(p.Mul64(step)) < 0 {
+			break
+		}
+		i++
+	}
+
+	ip, fp := q.QuoRem(p)
+	buf := ip.AppendDecimal(nil)
+	if !fp.IsZero() {
+		var digits [maxHumanPrec]byte
+		f := fp.lo // fp < 10^19 fits in 64 bits
+		for j := prec - 1; j >= 0; j-- {
+			digits[j] = byte('0' + f%10)
+			f /= 10
+		}
+		n := prec
+		for digits[n-1] == '0' {
+			n--
+		}
+		buf = append(append(buf, '.'), digits[:n]...)
+	}
+	return string(append(buf, units[i].name...))
+}
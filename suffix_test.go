@@ -0,0 +1,163 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPow10Tab(t *testing.T) {
+	p := big.NewInt(1)
+	for i, got := range pow10tab {
+		if fromBig(p) != got {
+			t.Errorf("pow10tab[%d] = %v; want %v", i, got, p)
+		}
+		p.Mul(p, big.NewInt(10))
+	}
+}
+
+func TestParseWithSuffix(t *testing.T) {
+	tests := []struct {
+		s    string
+		want uint128
+		err  error
+	}{
+		{"0", uint128{}, nil},
+		{"42", uint128{0, 42}, nil},
+		{"64Ki", uint128{0, 64 << 10}, nil},
+		{"1k", uint128{0, 1000}, nil},
+		{"1K", uint128{0, 1000}, nil},
+		{"3E", uint128{0, 3e18}, nil},
+		{"1.5Yi", uint128{0x18000, 0}, nil},
+		{"1.5k", uint128{0, 1500}, nil},
+		{"1.500Mi", uint128{0, 1536 << 10}, nil},
+		{".5k", uint128{0, 500}, nil},
+		{"2.", uint128{0, 2}, nil},
+		{"1Q", uint128{0, 1e15}.Mul(uint128{0, 1e15}), nil},
+		{"340282366920938463463374607431768211455", Max, nil},
+		{"0.000000000001818989403545856475830078125Yi", uint128{0, 1 << 41}, nil},
+		{"0.00000000000000000000000082718061255302767487140869206996285356581211090087890625Yi", uint128{0, 1}, nil},
+		{"281474976710655.99999999999999999999999917281938744697232512859130793003714643418788909912109375Yi", Max, nil},
+		{"1.000000000000000000000000000000000000000000001Q", uint128{}, ErrNotInteger},
+		{"281474976710655.99999999999999999999999917281938744697232512859130793003714643418788909912109376Yi", uint128{}, ErrNotInteger},
+		{"0." + strings.Repeat("0", 60) + "1k", uint128{}, ErrNotInteger},
+		{"1.3Ki", uint128{}, ErrNotInteger},
+		{"0.1", uint128{}, ErrNotInteger},
+		{"340282366920938463463374607431768211456", Max, strconv.ErrRange},
+		{"281474976710656Yi", Max, strconv.ErrRange},
+		{"340282366920938463463374607431768211456.5Ki", Max, strconv.ErrRange},
+		{"340283000Q", Max, strconv.ErrRange},
+		{"", uint128{}, strconv.ErrSyntax},
+		{"Ki", uint128{}, strconv.ErrSyntax},
+		{".", uint128{}, strconv.ErrSyntax},
+		{"1.2.3", uint128{}, strconv.ErrSyntax},
+		{"-1k", uint128{}, strconv.ErrSyntax},
+		{"1 k", uint128{}, strconv.ErrSyntax},
+		{"1kB", uint128{}, strconv.ErrSyntax},
+		{"1ki", uint128{}, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		got, err := ParseWithSuffix(tt.s)
+		if got != tt.want || !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("ParseWithSuffix(%q) = %v, %v; want %v, %v", tt.s, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestFormatSI(t *testing.T) {
+	tests := []struct {
+		u    uint128
+		prec int
+		si   string
+		iec  string
+	}{
+		{uint128{}, 1, "0", "0"},
+		{uint128{0, 999}, 1, "999", "999"},
+		{uint128{0, 1000}, 1, "1k", "1000"},
+		{uint128{0, 1024}, 1, "1k", "1Ki"},
+		{uint128{0, 1536}, 2, "1.54k", "1.5Ki"},
+		{uint128{0, 1234567}, 2, "1.23M", "1.18Mi"},
+		{uint128{0, 1234567}, 0, "1M", "1Mi"},
+		{uint128{0, 999960}, 1, "1M", "976.5Ki"},
+		{uint128{0, 1048575}, 2, "1.05M", "1Mi"},
+		{uint128{0, 120 << 40}, 1, "131.9T", "120Ti"},
+		{uint128{0, 3_400_000_000_000_000_000}, 1, "3.4E", "2.9Ei"},
+		{uint128{0, 1e15}.Mul(uint128{0, 1e15}).Mul64(1e6), 1, "1000000Q", "827180612553Yi"},
+		{Max, 3, "340282366.921Q", "281474976710656Yi"},
+		{Max, 100, "340282366.9209384634633746074Q", "281474976710656Yi"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.FormatSI(tt.prec); got != tt.si {
+			t.Errorf("%v.FormatSI(%d) = %q; want %q", tt.u, tt.prec, got, tt.si)
+		}
+		if got := tt.u.FormatIEC(tt.prec); got != tt.iec {
+			t.Errorf("%v.FormatIEC(%d) = %q; want %q", tt.u, tt.prec, got, tt.iec)
+		}
+	}
+	if got := (uint128{0, 3_400_000_000_000_000_000}).Mul64(1000).Humanize(); got != "3.4Z" {
+		t.Errorf("Humanize() = %q; want 3.4Z", got)
+	}
+	// Exactly representable results parse back to the same value.
+	for _, u := range []uint128{{0, 120 << 40}, {0, 1536}, {0, 5 << 50}} {
+		for _, f := range []string{u.FormatSI(19), u.FormatIEC(19)} {
+			if got, err := ParseWithSuffix(f); err != nil || got != u {
+				t.Errorf("ParseWithSuffix(%q) = %v, %v; want %v", f, got, err, u)
+			}
+		}
+	}
+}
+
+func TestParseFloatLike(t *testing.T) {
+	e30 := uint128{0, 1e15}.Mul(uint128{0, 1e15})
+	tests := []struct {
+		s    string
+		want uint128
+		err  error
+	}{
+		{"0", uint128{}, nil},
+		{"12", uint128{0, 12}, nil},
+		{"1e30", e30, nil},
+		{"1E30", e30, nil},
+		{"1e+30", e30, nil},
+		{"2.5e20", uint128{0, 25}.Mul(uint128{0, 1e19}), nil},
+		{"1.5E+3", uint128{0, 1500}, nil},
+		{"15000e-3", uint128{0, 15}, nil},
+		{".5e1", uint128{0, 5}, nil},
+		{"1.000", uint128{0, 1}, nil},
+		{"0e99999999999999999999", uint128{}, nil},
+		{"0.0e-5", uint128{}, nil},
+		{"3.40282366920938463463374607431768211455e38", Max, nil},
+		{"1" + strings.Repeat("0", 60) + "e-60", uint128{0, 1}, nil},
+		{"1.5e0", uint128{}, ErrNotInteger},
+		{"1e-1", uint128{}, ErrNotInteger},
+		{"1500e-4", uint128{}, ErrNotInteger},
+		{"1e39", Max, strconv.ErrRange},
+		{"1" + strings.Repeat("1", 60) + "e-60", uint128{}, ErrNotInteger},
+		{"3402823669209384634633746074317682114551e-1", uint128{}, ErrNotInteger},
+		{"3402823669209384634633746074317682114560e-1", Max, strconv.ErrRange},
+		{"1" + strings.Repeat("1", 60), Max, strconv.ErrRange},
+		{"3.5e38", Max, strconv.ErrRange},
+		{"1e99999999999999999999", Max, strconv.ErrRange},
+		{"", uint128{}, strconv.ErrSyntax},
+		{"e5", uint128{}, strconv.ErrSyntax},
+		{"1e", uint128{}, strconv.ErrSyntax},
+		{"1e+", uint128{}, strconv.ErrSyntax},
+		{"1e1.5", uint128{}, strconv.ErrSyntax},
+		{"1e5e5", uint128{}, strconv.ErrSyntax},
+		{"-1e5", uint128{}, strconv.ErrSyntax},
+		{"inf", uint128{}, strconv.ErrSyntax},
+		{"0x1p4", uint128{}, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		got, err := ParseFloatLike(tt.s)
+		if got != tt.want || !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("ParseFloatLike(%q) = %v, %v; want %v, %v", tt.s, got, err, tt.want, tt.err)
+		}
+	}
+}
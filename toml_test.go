@@ -0,0 +1,126 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	burntsushi "github.com/BurntSushi/toml"
+	pelletier "github.com/pelletier/go-toml/v2"
+)
+
+type tomlConfig struct {
+	ID  Uint128  `toml:"id"`
+	Ptr *Uint128 `toml:"ptr,omitempty"`
+}
+
+var tomlDecoders = []struct {
+	name   string
+	decode func(string, *tomlConfig) error
+	encode func(*tomlConfig) (string, error)
+}{
+	{
+		"BurntSushi",
+		func(s string, c *tomlConfig) error {
+			_, err := burntsushi.Decode(s, c)
+			return err
+		},
+		func(c *tomlConfig) (string, error) {
+			var b strings.Builder
+			err := burntsushi.NewEncoder(&b).Encode(c)
+			return b.String(), err
+		},
+	},
+	{
+		"pelletier",
+		func(s string, c *tomlConfig) error {
+			return pelletier.Unmarshal([]byte(s), c)
+		},
+		func(c *tomlConfig) (string, error) {
+			b, err := pelletier.Marshal(c)
+			return string(b), err
+		},
+	},
+}
+
+func TestTOML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint128
+		ok   bool
+	}{
+		{`id = 0`, uint128{}, true},
+		{`id = 255`, uint128{0, 255}, true},
+		{`id = 0xff`, uint128{0, 255}, true},
+		{`id = "255"`, uint128{0, 255}, true},
+		{`id = "0xFF"`, uint128{0, 255}, true},
+		{`id = 9223372036854775807`, uint128{0, 1<<63 - 1}, true},
+		{`id = "340282366920938463463374607431768211455"`, Max, true},
+		{`id = '18446744073709551616'`, uint128{1, 0}, true},
+		{`id = -1`, uint128{}, false},
+		{`id = 1.5`, uint128{}, false},
+		{`id = "abc"`, uint128{}, false},
+		{`id = true`, uint128{}, false},
+	}
+	for _, dec := range tomlDecoders {
+		for _, tt := range tests {
+			var c tomlConfig
+			err := dec.decode(tt.in, &c)
+			if (err == nil) != tt.ok || c.ID != tt.want {
+				t.Errorf("%s: decode(%q) = %v, %v; want %v, ok %v", dec.name, tt.in, c.ID, err, tt.want, tt.ok)
+			}
+		}
+	}
+}
+
+func TestTOMLOutOfRange(t *testing.T) {
+	// Values beyond 128 bits fail in UnmarshalText with strconv.ErrRange,
+	// and unquoted integers beyond 64 bits may already be rejected by
+	// the TOML decoder. Neither library wraps the error, so only the
+	// message can be checked.
+	for _, in := range []string{
+		`id = "340282366920938463463374607431768211456"`,
+		`id = 340282366920938463463374607431768211456`,
+		`id = 1e40`,
+	} {
+		for _, dec := range tomlDecoders {
+			var c tomlConfig
+			err := dec.decode(in, &c)
+			if err == nil {
+				t.Errorf("%s: decode(%q) = %v; want error", dec.name, in, c.ID)
+				continue
+			}
+			if !strings.Contains(err.Error(), "range") {
+				t.Errorf("%s: decode(%q) error = %q; want an out of range error", dec.name, in, err)
+			}
+		}
+	}
+}
+
+func TestTOMLRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, dec := range tomlDecoders {
+		for i := 0; i < 100; i++ {
+			u := randUint128(r).Rsh(uint(r.Intn(128)))
+			s, err := dec.encode(&tomlConfig{u, &u})
+			if err != nil {
+				t.Fatalf("%s: encode %v: %v", dec.name, u, err)
+			}
+			if !strings.Contains(s, strconv.Quote(u.String())) && !strings.Contains(s, "'"+u.String()+"'") {
+				t.Fatalf("%s: encode %v = %q; want a quoted string", dec.name, u, s)
+			}
+			var c tomlConfig
+			if err := dec.decode(s, &c); err != nil {
+				t.Fatalf("%s: decode(%q): %v", dec.name, s, err)
+			}
+			if c.ID != u || c.Ptr == nil || *c.Ptr != u {
+				t.Fatalf("%s: round trip of %v via %q = %+v", dec.name, u, s, c)
+			}
+		}
+	}
+}
@@ -9,18 +9,81 @@ import "math/bits"
 // uint128 represents a uint128 using two uint64s.
 //
 // When the methods below mention a bit number, bit 0 is the most
-// significant bit (in hi) and bit 127 is the lowest (lo&1).
+// significant bit (in hi) and bit 127 is the lowest (lo&1). Methods
+// with an LSB suffix use the math/bits convention instead, where bit 0
+// is the lowest.
+//
+// Uint128 implements driver.Valuer but not sql.Scanner, because its
+// Scan method implements fmt.Scanner. To read a Uint128 from a
+// database, scan into a NullUint128 or a Binary.
 type Uint128 struct {
 	hi uint64
 	lo uint64
 }
 
-// mask6 returns a uint128 bitmask with the topmost n bits of a
-// 128-bit number.
+// Commonly used values. They are variables only because Go has no
+// struct constants; do not modify them.
+var (
+	Zero = Uint128{}                       // 0
+	One  = Uint128{0, 1}                   // 1
+	Max  = Uint128{^uint64(0), ^uint64(0)} // 2^128-1
+)
+
+// New returns the Uint128 with the given high and low 64-bit halves,
+// that is hi<<64 | lo.
+func New(hi, lo uint64) Uint128 {
+	return Uint128{hi, lo}
+}
+
+// Hi returns the high (most significant) 64 bits of u.
+func (u Uint128) Hi() uint64 { return u.hi }
+
+// Lo returns the low (least significant) 64 bits of u.
+func (u Uint128) Lo() uint64 { return u.lo }
+
+// From4x32 returns the Uint128 made of the four 32-bit words w, most
+// significant first: w[0]<<96 | w[1]<<64 | w[2]<<32 | w[3].
+func From4x32(w [4]uint32) Uint128 {
+	return Uint128{
+		uint64(w[0])<<32 | uint64(w[1]),
+		uint64(w[2])<<32 | uint64(w[3]),
+	}
+}
+
+// To4x32 splits u into four 32-bit words, most significant first. It
+// is the inverse of From4x32.
+func (u Uint128) To4x32() [4]uint32 {
+	return [4]uint32{uint32(u.hi >> 32), uint32(u.hi), uint32(u.lo >> 32), uint32(u.lo)}
+}
+
+// Mask6 returns a Uint128 bitmask with the topmost n bits of a
+// 128-bit number set. Values of n outside [0, 128] are clamped to that
+// range: Mask6(n) is 0 for n <= 0 and all ones for n >= 128.
 func Mask6(n int) Uint128 {
+	switch {
+	case n <= 0:
+		return Uint128{}
+	case n >= 128:
+		return Max
+	}
 	return Uint128{^(^uint64(0) >> n), ^uint64(0) << (128 - n)}
 }
 
+// LowMask returns a Uint128 bitmask with the lowest n bits of a
+// 128-bit number set, the complement of Mask6(128-n). Like Mask6, it
+// clamps n to [0, 128].
+func LowMask(n int) Uint128 {
+	return Mask6(128 - n).Not()
+}
+
+// Mask returns a Uint128 bitmask with bits from through to inclusive
+// set, where bit 0 is the most significant bit.
+// It panics unless from <= to < 128.
+func Mask(from, to uint8) Uint128 {
+	w, s := fieldWidth(from, to)
+	return LowMask(w).Lsh(s)
+}
+
 // isZero reports whether u == 0.
 //
 // It's faster than u == (uint128{}) because the compiler (as of Go
@@ -28,6 +91,21 @@ func Mask6(n int) Uint128 {
 // its eq alg's generated code.
 func (u Uint128) IsZero() bool { return u.hi|u.lo == 0 }
 
+// IsMax reports whether u == Max, the largest Uint128.
+func (u Uint128) IsMax() bool { return u.hi&u.lo == ^uint64(0) }
+
+// Cmp compares u and v and returns -1 if u < v, 0 if u == v,
+// and +1 if u > v.
+func (u Uint128) Cmp(v Uint128) int {
+	switch {
+	case u.hi < v.hi || (u.hi == v.hi && u.lo < v.lo):
+		return -1
+	case u == v:
+		return 0
+	}
+	return +1
+}
+
 // and returns the bitwise AND of u and m (u&m).
 func (u Uint128) And(m Uint128) Uint128 {
 	return Uint128{u.hi & m.hi, u.lo & m.lo}
@@ -60,22 +138,68 @@ func (u Uint128) AddOne() Uint128 {
 	return Uint128{u.hi + carry, lo}
 }
 
+// Add returns u + m, wrapping around on overflow.
+func (u Uint128) Add(m Uint128) Uint128 {
+	lo, carry := bits.Add64(u.lo, m.lo, 0)
+	hi, _ := bits.Add64(u.hi, m.hi, carry)
+	return Uint128{hi, lo}
+}
+
+// Sub returns u - m, wrapping around on underflow.
+func (u Uint128) Sub(m Uint128) Uint128 {
+	lo, borrow := bits.Sub64(u.lo, m.lo, 0)
+	hi, _ := bits.Sub64(u.hi, m.hi, borrow)
+	return Uint128{hi, lo}
+}
+
+// AddCarry returns the sum with carry of u, m and carry: sum = u + m + carry.
+// The carry input must be 0 or 1; otherwise the behavior is undefined.
+// The carryOut output is guaranteed to be 0 or 1.
+//
+// It mirrors bits.Add64 so that Uint128 values can be chained as limbs
+// of wider multi-precision numbers.
+func (u Uint128) AddCarry(m Uint128, carry uint64) (sum Uint128, carryOut uint64) {
+	sum.lo, carry = bits.Add64(u.lo, m.lo, carry)
+	sum.hi, carryOut = bits.Add64(u.hi, m.hi, carry)
+	return
+}
+
+// SubBorrow returns the difference of u, m and borrow: diff = u - m - borrow.
+// The borrow input must be 0 or 1; otherwise the behavior is undefined.
+// The borrowOut output is guaranteed to be 0 or 1.
+//
+// It mirrors bits.Sub64 so that Uint128 values can be chained as limbs
+// of wider multi-precision numbers.
+func (u Uint128) SubBorrow(m Uint128, borrow uint64) (diff Uint128, borrowOut uint64) {
+	diff.lo, borrow = bits.Sub64(u.lo, m.lo, borrow)
+	diff.hi, borrowOut = bits.Sub64(u.hi, m.hi, borrow)
+	return
+}
+
+// Split returns the high and low 64-bit halves of u, the inverse of
+// New.
+func (u Uint128) Split() (hi, lo uint64) { return u.hi, u.lo }
+
 // halves returns the two uint64 halves of the uint128.
 //
 // Logically, think of it as returning two uint64s.
 // It only returns pointers for inlining reasons on 32-bit platforms.
+//
+// Deprecated: The pointers alias u, force it to escape and make
+// accidental writes through them easy. Use Split, or Hi and Lo.
 func (u *Uint128) Halves() [2]*uint64 {
 	return [2]*uint64{&u.hi, &u.lo}
 }
 
 // bitsSetFrom returns a copy of u with the given bit
-// and all subsequent ones set.
+// and all subsequent ones set. For bit >= 128 it returns u unchanged.
 func (u Uint128) BitsSetFrom(bit uint8) Uint128 {
 	return u.Or(Mask6(int(bit)).Not())
 }
 
 // bitsClearedFrom returns a copy of u with the given bit
-// and all subsequent ones cleared.
+// and all subsequent ones cleared. For bit >= 128 it returns u
+// unchanged.
 func (u Uint128) BitsClearedFrom(bit uint8) Uint128 {
 	return u.And(Mask6(int(bit)))
 }
@@ -5,11 +5,94 @@
 package uint128
 
 import (
+	"math/big"
+	"math/rand"
 	"testing"
 )
 
 type uint128 = Uint128
 
+// toBig returns u as a *big.Int, for checking results against math/big.
+func toBig(u uint128) *big.Int {
+	b := new(big.Int).SetUint64(u.hi)
+	b.Lsh(b, 64)
+	return b.Or(b, new(big.Int).SetUint64(u.lo))
+}
+
+// fromBig returns the low 128 bits of the non-negative b.
+func fromBig(b *big.Int) uint128 {
+	lo := new(big.Int).And(b, new(big.Int).SetUint64(^uint64(0)))
+	hi := new(big.Int).Rsh(b, 64)
+	return uint128{hi.Uint64(), lo.Uint64()}
+}
+
+// randUint128 returns a pseudo-random value whose bit length is itself
+// uniformly distributed, so that both small and large operands are
+// exercised.
+func randUint128(r *rand.Rand) uint128 {
+	u := uint128{r.Uint64(), r.Uint64()}
+	n := r.Intn(129)
+	if n <= 64 {
+		return uint128{0, u.lo >> (64 - n)}
+	}
+	return uint128{u.hi >> (128 - n), u.lo}
+}
+
+func TestNewHiLo(t *testing.T) {
+	for _, tt := range []struct{ hi, lo uint64 }{
+		{0, 0}, {0, 1}, {1, 0}, {^uint64(0), ^uint64(0)}, {0x0123456789abcdef, 0xfedcba9876543210},
+	} {
+		u := New(tt.hi, tt.lo)
+		if u != (uint128{tt.hi, tt.lo}) {
+			t.Errorf("New(%#x, %#x) = %v", tt.hi, tt.lo, u)
+		}
+		if u.Hi() != tt.hi || u.Lo() != tt.lo {
+			t.Errorf("New(%#x, %#x).Hi(), Lo() = %#x, %#x", tt.hi, tt.lo, u.Hi(), u.Lo())
+		}
+		if hi, lo := u.Split(); hi != tt.hi || lo != tt.lo {
+			t.Errorf("New(%#x, %#x).Split() = %#x, %#x", tt.hi, tt.lo, hi, lo)
+		}
+	}
+}
+
+func TestSentinels(t *testing.T) {
+	if !Zero.IsZero() || One != (uint128{0, 1}) || Max != Zero.SubOne() {
+		t.Errorf("Zero, One, Max = %#x, %#x, %#x", Zero, One, Max)
+	}
+	for _, tt := range []struct {
+		u    uint128
+		want bool
+	}{
+		{Max, true},
+		{Zero, false},
+		{uint128{^uint64(0), ^uint64(0) - 1}, false},
+		{uint128{^uint64(0) >> 1, ^uint64(0)}, false},
+		{uint128{0, ^uint64(0)}, false},
+	} {
+		if got := tt.u.IsMax(); got != tt.want {
+			t.Errorf("%#x.IsMax() = %v; want %v", tt.u, got, tt.want)
+		}
+	}
+}
+
+func Test4x32(t *testing.T) {
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	want := [4]uint32{0x01234567, 0x89abcdef, 0xfedcba98, 0x76543210}
+	if got := u.To4x32(); got != want {
+		t.Errorf("%#x.To4x32() = %#x; want %#x", u, got, want)
+	}
+	if got := From4x32(want); got != u {
+		t.Errorf("From4x32(%#x) = %#x; want %#x", want, got, u)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		u := randUint128(r)
+		if got := From4x32(u.To4x32()); got != u {
+			t.Fatalf("From4x32(%v.To4x32()) = %v", u, got)
+		}
+	}
+}
+
 func TestUint128AddSub(t *testing.T) {
 	const add1 = 1
 	const sub1 = -1
@@ -46,6 +129,72 @@ func TestUint128AddSub(t *testing.T) {
 	}
 }
 
+func TestUint128AddSubFull(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b uint128
+		sum  uint128
+		diff uint128
+	}{
+		{uint128{0, 0}, uint128{0, 0}, uint128{0, 0}, uint128{0, 0}},
+		{uint128{0, 5}, uint128{0, 3}, uint128{0, 8}, uint128{0, 2}},
+		{uint128{0, ^uint64(0)}, uint128{0, 1}, uint128{1, 0}, uint128{0, ^uint64(0) - 1}},
+		{uint128{1, 0}, uint128{0, 1}, uint128{1, 1}, uint128{0, ^uint64(0)}},
+		{uint128{3, 7}, uint128{1, 9}, uint128{4, 16}, uint128{1, ^uint64(0) - 1}},
+		{max, uint128{0, 1}, uint128{0, 0}, uint128{^uint64(0), ^uint64(0) - 1}},
+		{uint128{0, 0}, uint128{0, 1}, uint128{0, 1}, max},
+		{max, max, uint128{^uint64(0), ^uint64(0) - 1}, uint128{0, 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Add(tt.b); got != tt.sum {
+			t.Errorf("%v.Add(%v) = %v; want %v", tt.a, tt.b, got, tt.sum)
+		}
+		if got := tt.a.Sub(tt.b); got != tt.diff {
+			t.Errorf("%v.Sub(%v) = %v; want %v", tt.a, tt.b, got, tt.diff)
+		}
+		if got := tt.a.Sub(tt.b).Add(tt.b); got != tt.a {
+			t.Errorf("%v.Sub(%v).Add(%v) = %v; want %v", tt.a, tt.b, tt.b, got, tt.a)
+		}
+	}
+}
+
+func TestAddCarrySubBorrow(t *testing.T) {
+	max := uint128{^uint64(0), ^uint64(0)}
+	tests := []struct {
+		a, b   uint128
+		in     uint64
+		sum    uint128
+		carry  uint64
+		diff   uint128
+		borrow uint64
+	}{
+		{uint128{0, 0}, uint128{0, 0}, 0, uint128{0, 0}, 0, uint128{0, 0}, 0},
+		{uint128{0, 0}, uint128{0, 0}, 1, uint128{0, 1}, 0, max, 1},
+		{max, uint128{0, 0}, 1, uint128{0, 0}, 1, uint128{^uint64(0), ^uint64(0) - 1}, 0},
+		{max, uint128{0, 1}, 0, uint128{0, 0}, 1, uint128{^uint64(0), ^uint64(0) - 1}, 0},
+		{max, max, 1, max, 1, max, 1},
+		{uint128{0, ^uint64(0)}, uint128{0, 0}, 1, uint128{1, 0}, 0, uint128{0, ^uint64(0) - 1}, 0},
+		{uint128{1, 0}, uint128{1, 0}, 1, uint128{2, 1}, 0, max, 1},
+	}
+	for _, tt := range tests {
+		sum, carry := tt.a.AddCarry(tt.b, tt.in)
+		if sum != tt.sum || carry != tt.carry {
+			t.Errorf("%v.AddCarry(%v, %d) = %v, %d; want %v, %d", tt.a, tt.b, tt.in, sum, carry, tt.sum, tt.carry)
+		}
+		diff, borrow := tt.a.SubBorrow(tt.b, tt.in)
+		if diff != tt.diff || borrow != tt.borrow {
+			t.Errorf("%v.SubBorrow(%v, %d) = %v, %d; want %v, %d", tt.a, tt.b, tt.in, diff, borrow, tt.diff, tt.borrow)
+		}
+	}
+
+	// Chain two limbs into a 256-bit addition: (2^128-1) + 1 = 2^128.
+	lo, c := max.AddCarry(uint128{0, 1}, 0)
+	hi, c := uint128{}.AddCarry(uint128{}, c)
+	if lo != (uint128{}) || hi != (uint128{0, 1}) || c != 0 {
+		t.Errorf("256-bit chain = %v:%v carry %d; want 1:0 carry 0", hi, lo, c)
+	}
+}
+
 func TestBitsSetFrom(t *testing.T) {
 	tests := []struct {
 		bit  uint8
@@ -89,3 +238,122 @@ func TestBitsClearedFrom(t *testing.T) {
 		}
 	}
 }
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		a, b uint128
+		want int
+	}{
+		{uint128{0, 0}, uint128{0, 0}, 0},
+		{uint128{0, 1}, uint128{0, 2}, -1},
+		{uint128{0, 2}, uint128{0, 1}, +1},
+		{uint128{1, 0}, uint128{0, ^uint64(0)}, +1},
+		{uint128{0, ^uint64(0)}, uint128{1, 0}, -1},
+		{uint128{1, 5}, uint128{1, 5}, 0},
+		{uint128{1, 5}, uint128{2, 0}, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Cmp(tt.b); got != tt.want {
+			t.Errorf("%v.Cmp(%v) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLowMask(t *testing.T) {
+	tests := []struct {
+		n    int
+		want uint128
+	}{
+		{0, uint128{0, 0}},
+		{1, uint128{0, 1}},
+		{63, uint128{0, ^uint64(0) >> 1}},
+		{64, uint128{0, ^uint64(0)}},
+		{65, uint128{1, ^uint64(0)}},
+		{127, uint128{^uint64(0) >> 1, ^uint64(0)}},
+		{128, uint128{^uint64(0), ^uint64(0)}},
+	}
+	for _, tt := range tests {
+		if got := LowMask(tt.n); got != tt.want {
+			t.Errorf("LowMask(%d) = %#x; want %#x", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		from, to uint8
+		want     uint128
+	}{
+		{0, 0, uint128{1 << 63, 0}},
+		{0, 127, uint128{^uint64(0), ^uint64(0)}},
+		{127, 127, uint128{0, 1}},
+		{0, 63, uint128{^uint64(0), 0}},
+		{64, 127, uint128{0, ^uint64(0)}},
+		{60, 67, uint128{0xf, 0xf << 60}},
+		{4, 7, uint128{0x0f << 56, 0}},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.from, tt.to); got != tt.want {
+			t.Errorf("Mask(%d, %d) = %#x; want %#x", tt.from, tt.to, got, tt.want)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Mask(1, 0) did not panic")
+		}
+	}()
+	Mask(1, 0)
+}
+
+func TestMask6(t *testing.T) {
+	ones := uint128{^uint64(0), ^uint64(0)}
+	// Build the expected masks bit by bit for the whole valid range.
+	want := uint128{}
+	for n := 0; n <= 128; n++ {
+		if got := Mask6(n); got != want {
+			t.Errorf("Mask6(%d) = %#x; want %#x", n, got, want)
+		}
+		if got := LowMask(128 - n); got != want.Not() {
+			t.Errorf("LowMask(%d) = %#x; want %#x", 128-n, got, want.Not())
+		}
+		if n < 128 {
+			want = want.SetBit(uint8(n))
+		}
+	}
+	for _, n := range []int{-1, -64, -128, -1 << 31} {
+		if got := Mask6(n); got != (uint128{}) {
+			t.Errorf("Mask6(%d) = %#x; want 0", n, got)
+		}
+		if got := LowMask(n); got != (uint128{}) {
+			t.Errorf("LowMask(%d) = %#x; want 0", n, got)
+		}
+	}
+	for _, n := range []int{129, 192, 256, 1 << 31} {
+		if got := Mask6(n); got != ones {
+			t.Errorf("Mask6(%d) = %#x; want all ones", n, got)
+		}
+		if got := LowMask(n); got != ones {
+			t.Errorf("LowMask(%d) = %#x; want all ones", n, got)
+		}
+	}
+}
+
+func TestBitsFromBoundaries(t *testing.T) {
+	u := uint128{0x0123456789abcdef, 0xfedcba9876543210}
+	for bit := 0; bit < 256; bit++ {
+		b := uint8(bit)
+		var wantSet, wantCleared uint128
+		if bit >= 128 {
+			wantSet, wantCleared = u, u
+		} else {
+			tail := LowMask(128 - bit)
+			wantSet, wantCleared = u.Or(tail), u.And(tail.Not())
+		}
+		if got := u.BitsSetFrom(b); got != wantSet {
+			t.Errorf("%#x.BitsSetFrom(%d) = %#x; want %#x", u, bit, got, wantSet)
+		}
+		if got := u.BitsClearedFrom(b); got != wantCleared {
+			t.Errorf("%#x.BitsClearedFrom(%d) = %#x; want %#x", u, bit, got, wantCleared)
+		}
+	}
+}
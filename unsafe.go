@@ -0,0 +1,37 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import "unsafe"
+
+// The view helpers below expose the in-memory representation of a
+// Uint128 without copying. That representation is the hi word
+// followed by the lo word, each stored in the host's native byte
+// order (binary.NativeEndian). It therefore matches Bytes only on
+// big-endian hosts and matches neither Bytes nor BytesLE on
+// little-endian ones; code that writes the bytes somewhere another
+// machine may read them must either use Bytes/BytesLE or byte-swap
+// each word itself.
+//
+// Views into []byte have alignment 1, so converting from a Uint128 is
+// always safe. The reverse direction is deliberately not provided: an
+// arbitrary []byte need not be 8-byte aligned.
+
+// AsBytes returns a view of the 16 bytes of *u. Writes through the
+// returned array modify *u. See the layout notes above.
+func AsBytes(u *Uint128) *[16]byte {
+	return (*[16]byte)(unsafe.Pointer(u))
+}
+
+// SliceAsBytes returns a view of the elements of s as a byte slice of
+// length 16*len(s), sharing s's memory. Element i occupies bytes
+// [16*i, 16*i+16) with the layout of AsBytes. It returns nil if s is
+// empty.
+func SliceAsBytes(s []Uint128) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(s))), len(s)*int(unsafe.Sizeof(Uint128{})))
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/binary"
+	"testing"
+	"unsafe"
+)
+
+func TestAsBytes(t *testing.T) {
+	if unsafe.Sizeof(uint128{}) != 16 {
+		t.Fatalf("unsafe.Sizeof(Uint128{}) = %d; want 16", unsafe.Sizeof(uint128{}))
+	}
+	u := uint128{0x0001020304050607, 0x08090a0b0c0d0e0f}
+	b := AsBytes(&u)
+	if hi, lo := binary.NativeEndian.Uint64(b[:8]), binary.NativeEndian.Uint64(b[8:]); hi != u.hi || lo != u.lo {
+		t.Errorf("AsBytes(%#x) = %x; decodes to %#x, %#x", u, *b, hi, lo)
+	}
+	binary.NativeEndian.PutUint64(b[8:], 42)
+	if u != (uint128{0x0001020304050607, 42}) {
+		t.Errorf("write through AsBytes view: u = %#x", u)
+	}
+}
+
+func TestSliceAsBytes(t *testing.T) {
+	if got := SliceAsBytes(nil); got != nil {
+		t.Errorf("SliceAsBytes(nil) = %x; want nil", got)
+	}
+	s := []uint128{{1, 2}, {3, 4}, {5, 6}}
+	b := SliceAsBytes(s)
+	if len(b) != 48 {
+		t.Fatalf("len(SliceAsBytes) = %d; want 48", len(b))
+	}
+	for i := range s {
+		if got := *(*[16]byte)(b[16*i:]); got != *AsBytes(&s[i]) {
+			t.Errorf("element %d: view %x; want %x", i, got, *AsBytes(&s[i]))
+		}
+	}
+	b[16] ^= 0xff
+	if s[1] == (uint128{3, 4}) {
+		t.Errorf("write through SliceAsBytes view did not modify s[1]")
+	}
+}
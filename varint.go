@@ -0,0 +1,112 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"errors"
+	"io"
+)
+
+// The varint functions encode a Uint128 as unsigned LEB128, the format
+// of binary.PutUvarint extended to 128 bits: seven bits per byte, least
+// significant group first, with the high bit of each byte set if more
+// bytes follow. DWARF, WebAssembly and Protocol Buffers use the same
+// format, and values below 2^64 encode exactly as they do with
+// encoding/binary.
+
+// MaxVarintLen is the maximum length of a varint-encoded Uint128.
+const MaxVarintLen = 19
+
+var errVarintOverflow = errors.New("uint128: varint overflows a 128-bit integer")
+
+// AppendVarint appends the varint encoding of u to dst, the 128-bit
+// extension of binary.AppendUvarint and protowire.AppendVarint, and
+// returns the extended buffer.
+func (u Uint128) AppendVarint(dst []byte) []byte {
+	for u.hi != 0 || u.lo >= 0x80 {
+		dst = append(dst, byte(u.lo)|0x80)
+		u = u.Rsh(7)
+	}
+	return append(dst, byte(u.lo))
+}
+
+// PutUvarint encodes u into buf and returns the number of bytes
+// written. If the buffer is too small, PutUvarint will panic.
+func PutUvarint(buf []byte, u Uint128) int {
+	i := 0
+	for u.hi != 0 || u.lo >= 0x80 {
+		buf[i] = byte(u.lo) | 0x80
+		u = u.Rsh(7)
+		i++
+	}
+	buf[i] = byte(u.lo)
+	return i + 1
+}
+
+// Uvarint decodes a Uint128 from buf and returns that value and the
+// number of bytes read (> 0). If an error occurred, the value is 0
+// and the number of bytes n is <= 0 meaning:
+//   - n == 0: buf too small;
+//   - n < 0: value larger than 128 bits (overflow) and -n is the
+//     number of bytes read.
+func Uvarint(buf []byte) (Uint128, int) {
+	var u Uint128
+	for i, c := range buf {
+		if i == MaxVarintLen-1 && c > 3 {
+			// The last byte holds bits 126 and 127 only.
+			return Uint128{}, -(i + 1)
+		}
+		u = u.Or(Uint128{0, uint64(c & 0x7f)}.Lsh(uint(7 * i)))
+		if c < 0x80 {
+			return u, i + 1
+		}
+	}
+	return Uint128{}, 0
+}
+
+// ReadUvarint reads an encoded Uint128 from r. The error is io.EOF
+// only if no bytes were read. If an io.EOF happens after reading some
+// but not all the bytes, ReadUvarint returns io.ErrUnexpectedEOF.
+func ReadUvarint(r io.ByteReader) (Uint128, error) {
+	var u Uint128
+	for i := 0; i < MaxVarintLen; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			if i > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return u, err
+		}
+		if i == MaxVarintLen-1 && c > 3 {
+			return u, errVarintOverflow
+		}
+		u = u.Or(Uint128{0, uint64(c & 0x7f)}.Lsh(uint(7 * i)))
+		if c < 0x80 {
+			return u, nil
+		}
+	}
+	return u, errVarintOverflow
+}
+
+// EncodeZigZag returns the ZigZag encoding of s, which holds a signed
+// 128-bit integer in two's complement: 0, -1, 1, -2, ... map to
+// 0, 1, 2, 3, ..., so that values of small magnitude have short
+// varint encodings. It matches protowire.EncodeZigZag, the sint64
+// encoding of Protocol Buffers, for values in the int64 range. Since
+// there is no signed 128-bit type, s is a bit pattern such as the one
+// returned by From(int64(-5)).
+func EncodeZigZag(s Uint128) Uint128 {
+	sign := Uint128{}
+	if s.hi>>63 != 0 {
+		sign = Max
+	}
+	return s.Lsh(1).Xor(sign)
+}
+
+// DecodeZigZag returns the two's-complement signed 128-bit integer
+// whose ZigZag encoding is u. It is the inverse of EncodeZigZag.
+func DecodeZigZag(u Uint128) Uint128 {
+	return u.Rsh(1).Xor(Uint128{0, u.lo & 1}.Neg())
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestVarint(t *testing.T) {
+	tests := []uint128{
+		{}, {0, 1}, {0, 0x7f}, {0, 0x80}, {0, 300}, {0, ^uint64(0)},
+		{1, 0}, {1 << 62, 0}, {1 << 63, 0}, Max,
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		tests = append(tests, randUint128(r).Rsh(uint(r.Intn(128))))
+	}
+	for _, u := range tests {
+		b := u.AppendVarint([]byte{0xee})[1:]
+		if want := (u.Len() + 6) / 7; len(b) != max(want, 1) {
+			t.Errorf("%v.AppendVarint() = %x; want %d bytes", u, b, want)
+		}
+		if u.hi == 0 {
+			if want := protowire.AppendVarint(nil, u.lo); !bytes.Equal(b, want) {
+				t.Errorf("%v.AppendVarint() = %x; want %x", u, b, want)
+			}
+		}
+		got, n, err := ConsumeVarint(append(b, 0xff))
+		if err != nil || got != u || n != len(b) {
+			t.Errorf("ConsumeVarint(%x) = %v, %d, %v; want %v, %d", b, got, n, err, u, len(b))
+		}
+	}
+	if n := len(Max.AppendVarint(nil)); n != MaxVarintLen {
+		t.Errorf("len(Max.AppendVarint()) = %d; want MaxVarintLen = %d", n, MaxVarintLen)
+	}
+
+	for _, in := range []string{
+		"",
+		"80",
+		"ffffffffffffffffffffffffffffffffffffff",
+		"ffffffffffffffffffffffffffffffffffff04", // 2^128
+		"ffffffffffffffffffffffffffffffffffff8300",
+	} {
+		b, _ := hex.DecodeString(in)
+		if got, n, err := ConsumeVarint(b); err == nil {
+			t.Errorf("ConsumeVarint(%s) = %v, %d; want error", in, got, n)
+		}
+	}
+	// Non-minimal encodings are accepted, as by protowire.
+	if got, n, err := ConsumeVarint([]byte{0x81, 0x80, 0x00}); err != nil || got != (uint128{0, 1}) || n != 3 {
+		t.Errorf("ConsumeVarint(818000) = %v, %d, %v; want 1, 3", got, n, err)
+	}
+}
+
+func TestUvarint(t *testing.T) {
+	tests := []uint128{{}, {0, 1}, {0, 0x80}, {0, ^uint64(0)}, {1, 0}, Max}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		tests = append(tests, randUint128(r).Rsh(uint(r.Intn(128))))
+	}
+	var buf [MaxVarintLen]byte
+	for _, u := range tests {
+		n := PutUvarint(buf[:], u)
+		if want := u.AppendVarint(nil); !bytes.Equal(buf[:n], want) {
+			t.Errorf("PutUvarint(%v) = %x; want %x", u, buf[:n], want)
+		}
+		if u.hi == 0 {
+			var b64 [binary.MaxVarintLen64]byte
+			if m := binary.PutUvarint(b64[:], u.lo); !bytes.Equal(buf[:n], b64[:m]) {
+				t.Errorf("PutUvarint(%v) = %x; binary.PutUvarint = %x", u, buf[:n], b64[:m])
+			}
+		}
+		if got, m := Uvarint(buf[:n]); got != u || m != n {
+			t.Errorf("Uvarint(%x) = %v, %d; want %v, %d", buf[:n], got, m, u, n)
+		}
+		if got, m := Uvarint(buf[:n-1]); got != (uint128{}) || m != 0 {
+			t.Errorf("Uvarint(%x) = %v, %d; want 0, 0", buf[:n-1], got, m)
+		}
+		if got, err := ReadUvarint(bytes.NewReader(buf[:n])); err != nil || got != u {
+			t.Errorf("ReadUvarint(%x) = %v, %v; want %v", buf[:n], got, err, u)
+		}
+		if _, err := ReadUvarint(bytes.NewReader(buf[:n-1])); err != io.ErrUnexpectedEOF && n > 1 {
+			t.Errorf("ReadUvarint(%x) error = %v; want io.ErrUnexpectedEOF", buf[:n-1], err)
+		}
+	}
+
+	if _, err := ReadUvarint(bytes.NewReader(nil)); err != io.EOF {
+		t.Errorf("ReadUvarint(empty) error = %v; want io.EOF", err)
+	}
+	for _, tt := range []struct {
+		in string
+		n  int
+	}{
+		{"ffffffffffffffffffffffffffffffffffff04", -19},
+		{"ffffffffffffffffffffffffffffffffffffff01", -19},
+	} {
+		b, _ := hex.DecodeString(tt.in)
+		if got, n := Uvarint(b); got != (uint128{}) || n != tt.n {
+			t.Errorf("Uvarint(%s) = %v, %d; want 0, %d", tt.in, got, n, tt.n)
+		}
+		if _, err := ReadUvarint(bytes.NewReader(b)); err != errVarintOverflow {
+			t.Errorf("ReadUvarint(%s) error = %v; want overflow", tt.in, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("PutUvarint into a short buffer did not panic")
+		}
+	}()
+	PutUvarint(make([]byte, 18), Max)
+}
+
+func TestZigZag(t *testing.T) {
+	tests := []struct {
+		s, want uint128
+	}{
+		{uint128{}, uint128{}},
+		{From(-1), uint128{0, 1}},
+		{From(1), uint128{0, 2}},
+		{From(-2), uint128{0, 3}},
+		{uint128{1<<63 - 1, ^uint64(0)}, Max.Sub(uint128{0, 1})}, // 2^127-1
+		{uint128{1 << 63, 0}, Max},                               // -2^127
+	}
+	for _, tt := range tests {
+		if got := EncodeZigZag(tt.s); got != tt.want {
+			t.Errorf("EncodeZigZag(%#x) = %#x; want %#x", tt.s, got, tt.want)
+		}
+		if got := DecodeZigZag(tt.want); got != tt.s {
+			t.Errorf("DecodeZigZag(%#x) = %#x; want %#x", tt.want, got, tt.s)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x := int64(r.Uint64()) >> r.Intn(64)
+		if got, want := EncodeZigZag(From(x)), protowire.EncodeZigZag(x); got != (uint128{0, want}) {
+			t.Fatalf("EncodeZigZag(%d) = %v; want %d", x, got, want)
+		}
+		if got := DecodeZigZag(uint128{0, uint64(x)}); got != From(protowire.DecodeZigZag(uint64(x))) {
+			t.Fatalf("DecodeZigZag(%d) = %#x; want %d", uint64(x), got, protowire.DecodeZigZag(uint64(x)))
+		}
+
+		s := randUint128(r)
+		z := EncodeZigZag(s)
+		if got := DecodeZigZag(z); got != s {
+			t.Fatalf("DecodeZigZag(EncodeZigZag(%#x)) = %#x", s, got)
+		}
+		if s.hi>>63 == 0 && z != s.Lsh(1) || s.hi>>63 != 0 && z != s.Neg().Lsh(1).Sub(uint128{0, 1}) {
+			t.Fatalf("EncodeZigZag(%#x) = %#x", s, z)
+		}
+	}
+}
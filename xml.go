@@ -0,0 +1,38 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// MarshalXML implements xml.Marshaler. The element content is the
+// decimal representation of u.
+func (u Uint128) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(u.String(), start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler. The element content may be
+// decimal, or hexadecimal with a 0x prefix, as accepted by
+// UnmarshalText, and surrounding whitespace is ignored.
+func (u *Uint128) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return u.UnmarshalText([]byte(strings.TrimSpace(s)))
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr, writing u in decimal.
+func (u Uint128) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: u.String()}, nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr. It accepts the same
+// forms as UnmarshalXML.
+func (u *Uint128) UnmarshalXMLAttr(attr xml.Attr) error {
+	return u.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
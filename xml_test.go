@@ -0,0 +1,68 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"encoding/xml"
+	"math/rand"
+	"testing"
+)
+
+type xmlRecord struct {
+	XMLName xml.Name `xml:"record"`
+	Serial  Uint128  `xml:"serial,attr"`
+	ID      Uint128  `xml:"id"`
+	Ptr     *Uint128 `xml:"ptr,omitempty"`
+}
+
+func TestXML(t *testing.T) {
+	b, err := xml.Marshal(xmlRecord{Serial: uint128{1, 0}, ID: Max})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `<record serial="18446744073709551616"><id>340282366920938463463374607431768211455</id></record>`
+	if string(b) != want {
+		t.Errorf("xml.Marshal = %s; want %s", b, want)
+	}
+
+	tests := []struct {
+		in         string
+		serial, id uint128
+		ok         bool
+	}{
+		{`<record serial="1"><id>2</id></record>`, uint128{0, 1}, uint128{0, 2}, true},
+		{`<record serial=" 0xff "><id>
+			0XFF
+		</id></record>`, uint128{0, 255}, uint128{0, 255}, true},
+		{`<record serial="1"><id>2<!-- two --></id></record>`, uint128{0, 1}, uint128{0, 2}, true},
+		{`<record serial="-1"><id>2</id></record>`, uint128{}, uint128{}, false},
+		{`<record serial="1"><id>abc</id></record>`, uint128{0, 1}, uint128{}, false},
+		{`<record serial="1"><id>340282366920938463463374607431768211456</id></record>`, uint128{0, 1}, uint128{}, false},
+		{`<record serial="1"><id><x>2</x></id></record>`, uint128{0, 1}, uint128{}, false},
+	}
+	for _, tt := range tests {
+		var r xmlRecord
+		err := xml.Unmarshal([]byte(tt.in), &r)
+		if (err == nil) != tt.ok || r.Serial != tt.serial || r.ID != tt.id {
+			t.Errorf("xml.Unmarshal(%q) = %v, %v, %v; want %v, %v, ok %v", tt.in, r.Serial, r.ID, err, tt.serial, tt.id, tt.ok)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		u, v := randUint128(rnd), randUint128(rnd)
+		b, err := xml.Marshal(xmlRecord{Serial: u, ID: v, Ptr: &u})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r xmlRecord
+		if err := xml.Unmarshal(b, &r); err != nil {
+			t.Fatalf("xml.Unmarshal(%s): %v", b, err)
+		}
+		if r.Serial != u || r.ID != v || r.Ptr == nil || *r.Ptr != u {
+			t.Fatalf("XML round trip via %s = %+v", b, r)
+		}
+	}
+}
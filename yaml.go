@@ -0,0 +1,25 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+// MarshalYAML implements the yaml.Marshaler interface of
+// gopkg.in/yaml.v2 and gopkg.in/yaml.v3. The value is a decimal string
+// scalar, which YAML encoders quote so that it round-trips as a string
+// rather than being read back as a lossy float.
+func (u Uint128) MarshalYAML() (interface{}, error) {
+	return u.String(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of
+// gopkg.in/yaml.v2, which gopkg.in/yaml.v3 and github.com/goccy/go-yaml
+// also accept. The scalar may be quoted or plain and holds decimal, or
+// hexadecimal with a 0x prefix, as accepted by UnmarshalText.
+func (u *Uint128) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return u.UnmarshalText([]byte(s))
+}
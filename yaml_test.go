@@ -0,0 +1,65 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uint128
+
+import (
+	"math/rand"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAML(t *testing.T) {
+	type config struct {
+		ID  Uint128  `yaml:"id"`
+		Ptr *Uint128 `yaml:"ptr"`
+	}
+	tests := []struct {
+		in   string
+		want uint128
+		ok   bool
+	}{
+		{"id: 0", uint128{}, true},
+		{"id: 255", uint128{0, 255}, true},
+		{`id: "255"`, uint128{0, 255}, true},
+		{"id: '18446744073709551616'", uint128{1, 0}, true},
+		{"id: 340282366920938463463374607431768211455", Max, true},
+		{"id: 0xff", uint128{0, 255}, true},
+		{`id: "0XFF"`, uint128{0, 255}, true},
+		{"id: 340282366920938463463374607431768211456", uint128{}, false},
+		{"id: -1", uint128{}, false},
+		{"id: 1.5", uint128{}, false},
+		{"id: [1]", uint128{}, false},
+		{"id: abc", uint128{}, false},
+	}
+	for _, tt := range tests {
+		var c config
+		err := yaml.Unmarshal([]byte(tt.in), &c)
+		if (err == nil) != tt.ok || c.ID != tt.want {
+			t.Errorf("yaml.Unmarshal(%q) = %v, %v; want %v, ok %v", tt.in, c.ID, err, tt.want, tt.ok)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		u := randUint128(r).Rsh(uint(r.Intn(128)))
+		b, err := yaml.Marshal(config{u, &u})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c config
+		if err := yaml.Unmarshal(b, &c); err != nil {
+			t.Fatalf("yaml.Unmarshal(%q): %v", b, err)
+		}
+		if c.ID != u || c.Ptr == nil || *c.Ptr != u {
+			t.Fatalf("YAML round trip of %v via %q = %+v", u, b, c)
+		}
+	}
+
+	b, _ := yaml.Marshal(config{ID: uint128{0, 255}})
+	if want := "id: \"255\"\nptr: null\n"; string(b) != want {
+		t.Errorf("yaml.Marshal = %q; want %q", b, want)
+	}
+}